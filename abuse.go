@@ -0,0 +1,502 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/VTGare/Eugen/database"
+	"github.com/VTGare/Eugen/utils"
+	"github.com/bwmarrin/discordgo"
+	"github.com/jasonlvhit/gocron"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// freshMemberAge is how long members are considered freshly joined by burst detection.
+	freshMemberAge = 7 * 24 * time.Hour
+	// authorCapPeriod is a period of anti-abuse daily cap.
+	authorCapPeriod = 24 * time.Hour
+)
+
+var (
+	votes = &voteTracker{
+		seen:       make(map[database.MessagePair]map[string]time.Time),
+		authors:    make(map[string][]*authorStar),
+		flagged:    make(map[string]time.Time),
+		discounted: make(map[database.MessagePair]map[string]string),
+	}
+	tallies = &tallyCache{entries: make(map[database.MessagePair]*tally)}
+)
+
+func init() {
+	go func() {
+		s := gocron.NewScheduler()
+		s.Every(1).Hour().Do(votes.sweep)
+		s.Every(10).Minutes().Do(tallies.sweep)
+		<-s.Start()
+	}()
+}
+
+// voteTracker remembers recent stars, since Discord doesn't tell when a reaction was added.
+type voteTracker struct {
+	mu sync.Mutex
+	// seen is when each user's star on a message was first counted.
+	seen map[database.MessagePair]map[string]time.Time
+	// authors are posts of an author starred by a user, keyed by guild, starrer and author.
+	authors map[string][]*authorStar
+	// flagged are messages already reported to the log channel, keyed by message and reason.
+	flagged map[string]time.Time
+	// discounted are reasons stars on a message were discounted for, keyed by starrer's ID.
+	// Once discounted, a star isn't counted again when it stops looking suspicious, e.g. when a burst is over.
+	discounted map[database.MessagePair]map[string]string
+}
+
+type authorStar struct {
+	messageID string
+	at        time.Time
+}
+
+// record remembers a star that was just added by a user.
+func (v *voteTracker) record(pair database.MessagePair, guildID, userID, authorID string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	users, ok := v.seen[pair]
+	if !ok {
+		users = make(map[string]time.Time)
+		v.seen[pair] = users
+	}
+
+	if _, ok := users[userID]; !ok {
+		users[userID] = time.Now()
+	}
+
+	if authorID == "" {
+		return
+	}
+
+	key := guildID + ":" + userID + ":" + authorID
+	for _, star := range v.authors[key] {
+		if star.messageID == pair.MessageID {
+			return
+		}
+	}
+
+	v.authors[key] = append(v.authors[key], &authorStar{messageID: pair.MessageID, at: time.Now()})
+}
+
+// forget removes a star that was taken back by a user.
+func (v *voteTracker) forget(pair database.MessagePair, guildID, userID, authorID string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	delete(v.seen[pair], userID)
+
+	key := guildID + ":" + userID + ":" + authorID
+	stars := make([]*authorStar, 0, len(v.authors[key]))
+	for _, star := range v.authors[key] {
+		if star.messageID != pair.MessageID {
+			stars = append(stars, star)
+		}
+	}
+
+	v.authors[key] = stars
+}
+
+// firstSeen returns when a star was added. Stars that weren't seen being added, e.g. before a restart, are zero and treated as old.
+func (v *voteTracker) firstSeen(pair database.MessagePair, userID string) time.Time {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	return v.seen[pair][userID]
+}
+
+// authorRank returns how many other posts of the author the user starred today before starring this one.
+// Stars that weren't seen being added aren't ranked.
+func (v *voteTracker) authorRank(guildID, userID, authorID, messageID string) int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	key := guildID + ":" + userID + ":" + authorID
+	rank := 0
+	for _, star := range v.authors[key] {
+		if time.Since(star.at) >= authorCapPeriod {
+			continue
+		}
+
+		if star.messageID == messageID {
+			return rank
+		}
+		rank++
+	}
+
+	return 0
+}
+
+// discount remembers reasons stars on a message were discounted for and returns all reasons remembered so far.
+func (v *voteTracker) discount(pair database.MessagePair, reasons map[string]string) map[string]string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	discounted, ok := v.discounted[pair]
+	if !ok {
+		discounted = make(map[string]string)
+		v.discounted[pair] = discounted
+	}
+
+	for userID, reason := range reasons {
+		if _, ok := discounted[userID]; !ok {
+			discounted[userID] = reason
+		}
+	}
+
+	all := make(map[string]string, len(discounted))
+	for userID, reason := range discounted {
+		all[userID] = reason
+	}
+
+	return all
+}
+
+// flag marks a message as reported for a reason and returns false if it already was.
+func (v *voteTracker) flag(pair database.MessagePair, reason string) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	key := pair.String() + " " + reason
+	if _, ok := v.flagged[key]; ok {
+		return false
+	}
+
+	v.flagged[key] = time.Now()
+	return true
+}
+
+// sweep forgets stars that are too old to matter for any of the checks.
+func (v *voteTracker) sweep() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	for pair, users := range v.seen {
+		latest := time.Time{}
+		for _, at := range users {
+			if at.After(latest) {
+				latest = at
+			}
+		}
+
+		if time.Since(latest) > freshMemberAge {
+			delete(v.seen, pair)
+		}
+	}
+
+	for pair := range v.discounted {
+		if _, ok := v.seen[pair]; !ok {
+			delete(v.discounted, pair)
+		}
+	}
+
+	for key, stars := range v.authors {
+		if len(stars) == 0 || time.Since(stars[len(stars)-1].at) > authorCapPeriod {
+			delete(v.authors, key)
+		}
+	}
+
+	for key, at := range v.flagged {
+		if time.Since(at) > freshMemberAge {
+			delete(v.flagged, key)
+		}
+	}
+}
+
+// tally is a cached list of message's starrers, so a star doesn't require fetching all of them again.
+type tally struct {
+	votes   map[string]*vote
+	expires time.Time
+}
+
+type tallyCache struct {
+	mu      sync.Mutex
+	entries map[database.MessagePair]*tally
+}
+
+// get returns a tally of a message or nil. Events of a message are serialized by the starboard queue,
+// so a returned tally is only changed by one event at a time.
+func (c *tallyCache) get(pair database.MessagePair) *tally {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t, ok := c.entries[pair]
+	if !ok || time.Now().After(t.expires) {
+		return nil
+	}
+
+	return t
+}
+
+func (c *tallyCache) set(pair database.MessagePair, t *tally) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t.expires = time.Now().Add(memberCacheTTL)
+	c.entries[pair] = t
+}
+
+func (c *tallyCache) drop(pair database.MessagePair) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, pair)
+}
+
+func (c *tallyCache) sweep() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for pair, t := range c.entries {
+		if time.Now().After(t.expires) {
+			delete(c.entries, pair)
+		}
+	}
+}
+
+// vote is a single star with information used by role and anti-abuse checks.
+type vote struct {
+	userID string
+	roles  []string
+	weight int
+	// joinedAt is zero if starrer isn't a member anymore.
+	joinedAt time.Time
+	// seen is zero if the star wasn't seen being added.
+	seen time.Time
+}
+
+// countVotes replaces star count with a sum of starrers' vote weights, ignoring star-banned users and discounting suspicious stars.
+// Messages with suspicious stars are reported to the log channel.
+func (se *StarboardEvent) countVotes() error {
+	pair := database.NewPair(se.message.ChannelID, se.message.ID)
+	if se.React == nil {
+		tallies.drop(pair)
+		return nil
+	}
+
+	if !se.guild.HasStarRoles() && !se.guild.HasAntiAbuse() && !se.guild.HasStarBans() {
+		return nil
+	}
+
+	t, err := se.tally(pair)
+	if err != nil {
+		return err
+	}
+
+	list := make([]*vote, 0, len(t.votes))
+	for _, v := range t.votes {
+		if !se.guild.Selfstar && se.message.Author != nil && v.userID == se.message.Author.ID {
+			continue
+		}
+
+		if se.guild.IsStarBanned(v.userID) {
+			continue
+		}
+
+		// Weights are computed on every count, so changes of star roles apply to cached starrers.
+		v.weight = se.guild.VoteWeight(v.roles)
+		v.seen = votes.firstSeen(pair, v.userID)
+		list = append(list, v)
+	}
+
+	suspicious := se.suspiciousVotes(list)
+
+	score := 0
+	for _, v := range list {
+		if _, ok := suspicious[v.userID]; !ok {
+			score += v.weight
+		}
+	}
+
+	se.React.Count = score
+	se.counted = true
+	if len(suspicious) != 0 && votes.flag(pair, "abuse") {
+		se.logAction(utils.ActionFlagged, suspiciousToString(suspicious))
+	}
+
+	return nil
+}
+
+// tally returns message's starrers. A cached tally is updated with the reacting user,
+// all starrers are only fetched if there's no tally or it doesn't match the reaction count.
+func (se *StarboardEvent) tally(pair database.MessagePair) (*tally, error) {
+	if t := tallies.get(pair); t != nil {
+		switch {
+		case se.addEvent != nil:
+			t.votes[se.addEvent.UserID] = se.newVote(se.addEvent.UserID)
+		case se.removeEvent != nil:
+			delete(t.votes, se.removeEvent.UserID)
+		}
+
+		// Stars below the star requirement aren't queued, so they're missing from the tally.
+		if len(t.votes) == se.React.Count {
+			return t, nil
+		}
+	}
+
+	users, err := reactors(se.session, se.message, se.React.Emoji)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &tally{votes: make(map[string]*vote, len(users))}
+	for _, user := range users {
+		t.votes[user.ID] = se.newVote(user.ID)
+	}
+
+	tallies.set(pair, t)
+	return t, nil
+}
+
+// newVote returns a star of a user with their roles and join date.
+func (se *StarboardEvent) newVote(userID string) *vote {
+	v := &vote{userID: userID}
+
+	// Members who left the server don't have any roles.
+	member, err := memberCache.member(se.session, se.guild.ID, userID)
+	if err != nil {
+		logrus.Debugf("memberCache.member(): %v", err)
+	} else {
+		v.roles = member.roles
+		v.joinedAt = member.joinedAt
+	}
+
+	return v
+}
+
+// trackVote records an added or forgets a removed star for anti-abuse checks.
+// It's called for every star, including ones that don't reach the star requirement and aren't queued.
+func trackVote(guild *database.Guild, msg *discordgo.Message, userID string, added bool) {
+	if !guild.HasAntiAbuse() {
+		return
+	}
+
+	var (
+		pair     = database.NewPair(msg.ChannelID, msg.ID)
+		authorID string
+	)
+
+	if msg.Author != nil {
+		authorID = msg.Author.ID
+	}
+
+	if added {
+		votes.record(pair, guild.ID, userID, authorID)
+	} else {
+		votes.forget(pair, guild.ID, userID, authorID)
+	}
+}
+
+// suspiciousVotes returns reasons to discount stars keyed by starrer's ID.
+func (se *StarboardEvent) suspiciousVotes(list []*vote) map[string]string {
+	reasons := make(map[string]string)
+	if !se.guild.HasAntiAbuse() {
+		return reasons
+	}
+
+	var (
+		a     = se.guild.AntiAbuse
+		now   = time.Now()
+		fresh = make([]*vote, 0)
+	)
+
+	for _, v := range list {
+		if v.weight == 0 {
+			continue
+		}
+
+		createdAt, _ := discordgo.SnowflakeTimestamp(v.userID)
+		switch {
+		case a.MinAccountAge > 0 && now.Sub(createdAt) < a.MinAccountAge:
+			reasons[v.userID] = fmt.Sprintf("account is %v old", utils.FormatDuration(now.Sub(createdAt)))
+		case a.MinMemberAge > 0 && v.joinedAt.IsZero():
+			reasons[v.userID] = "not a member"
+		case a.MinMemberAge > 0 && now.Sub(v.joinedAt) < a.MinMemberAge:
+			reasons[v.userID] = fmt.Sprintf("joined %v ago", utils.FormatDuration(now.Sub(v.joinedAt)))
+		case a.DailyAuthorCap > 0 && se.message.Author != nil &&
+			votes.authorRank(se.guild.ID, v.userID, se.message.Author.ID, se.message.ID) >= a.DailyAuthorCap:
+			reasons[v.userID] = fmt.Sprintf("starred more than %v posts of the author today", a.DailyAuthorCap)
+		}
+
+		if !v.joinedAt.IsZero() && v.seen.Sub(v.joinedAt) < freshMemberAge && !v.seen.IsZero() {
+			fresh = append(fresh, v)
+		}
+	}
+
+	// Bursts are measured between stars themselves, so they're still found after the window has passed.
+	if a.BurstSize > 0 && a.BurstWindow > 0 {
+		for v, size := range bursts(fresh, a.BurstSize, a.BurstWindow) {
+			if _, ok := reasons[v.userID]; !ok {
+				reasons[v.userID] = fmt.Sprintf("one of %v new members who starred within %v", size, utils.FormatDuration(a.BurstWindow))
+			}
+		}
+	}
+
+	discounted := votes.discount(database.NewPair(se.message.ChannelID, se.message.ID), reasons)
+	for _, v := range list {
+		if reason, ok := discounted[v.userID]; ok {
+			reasons[v.userID] = reason
+		}
+	}
+
+	return reasons
+}
+
+// bursts returns votes that were added within window of at least size other votes, with the size of the largest such burst.
+func bursts(list []*vote, size int, window time.Duration) map[*vote]int {
+	sorted := make([]*vote, len(list))
+	copy(sorted, list)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].seen.Before(sorted[j].seen) })
+
+	found := make(map[*vote]int)
+	last := 0
+	for start := range sorted {
+		if last < start {
+			last = start
+		}
+
+		for last+1 < len(sorted) && sorted[last+1].seen.Sub(sorted[start].seen) < window {
+			last++
+		}
+
+		n := last - start + 1
+		if n < size {
+			continue
+		}
+
+		for _, v := range sorted[start : last+1] {
+			if n > found[v] {
+				found[v] = n
+			}
+		}
+	}
+
+	return found
+}
+
+func suspiciousToString(reasons map[string]string) string {
+	var sb strings.Builder
+	sb.WriteString("Following stars weren't counted:")
+
+	i := 0
+	for userID, reason := range reasons {
+		if i == 20 {
+			sb.WriteString(fmt.Sprintf("\n…and %v more", len(reasons)-i))
+			break
+		}
+
+		sb.WriteString(fmt.Sprintf("\n<@%v>: %v", userID, reason))
+		i++
+	}
+
+	return sb.String()
+}
@@ -2,8 +2,8 @@ package main
 
 import (
 	"fmt"
-	"slices"
 	"strings"
+	"time"
 
 	"github.com/VTGare/Eugen/database"
 	"github.com/VTGare/Eugen/framework"
@@ -28,7 +28,7 @@ func onReady(s *discordgo.Session, e *discordgo.Ready) {
 }
 
 func trimPrefix(content, guildID string) string {
-	guild, ok := database.GuildCache[guildID]
+	guild, ok := database.GuildCache.Lookup(guildID)
 	var defaultPrefix bool
 	if ok && guild.Prefix == "e!" {
 		defaultPrefix = true
@@ -56,9 +56,39 @@ func trimPrefix(content, guildID string) string {
 	return content
 }
 
-func handleError(s *discordgo.Session, channelID string, err error) {
+// trimPrefixFold removes a lowercase prefix from content regardless of its case.
+// Lowercase letters can have a different length, so the prefix can't be cut by its length.
+func trimPrefixFold(content, prefix string) string {
+	for i := range content {
+		lower := strings.ToLower(content[:i])
+		if lower == prefix {
+			return content[i:]
+		}
+
+		if len(lower) > len(prefix) {
+			break
+		}
+	}
+
+	if strings.ToLower(content) == prefix {
+		return ""
+	}
+
+	return content
+}
+
+func handleError(s *discordgo.Session, m *discordgo.MessageCreate, err error) {
 	if err != nil {
 		log.Errorf("An error occured: %v", err)
+		if m.GuildID != "" {
+			utils.LogAction(s, &utils.LogEntry{
+				Action:  utils.ActionError,
+				GuildID: m.GuildID,
+				UserID:  m.Author.ID,
+				Details: fmt.Sprintf("``%v`` failed: %v", m.Content, err),
+			})
+		}
+
 		embed := &discordgo.MessageEmbed{
 			Title: "Oops, something went wrong!",
 			Thumbnail: &discordgo.MessageEmbedThumbnail{
@@ -68,7 +98,7 @@ func handleError(s *discordgo.Session, channelID string, err error) {
 			Color:       utils.EmbedColor,
 			Timestamp:   utils.EmbedTimestamp(),
 		}
-		s.ChannelMessageSendEmbed(channelID, embed)
+		s.ChannelMessageSendEmbed(m.ChannelID, embed)
 	}
 }
 
@@ -78,6 +108,7 @@ func messageCreated(s *discordgo.Session, m *discordgo.MessageCreate) {
 	}
 
 	isGuild := m.GuildID != ""
+	raw := m.Content
 	m.Content = strings.ToLower(m.Content)
 
 	where := func() string {
@@ -106,10 +137,16 @@ func messageCreated(s *discordgo.Session, m *discordgo.MessageCreate) {
 				s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("%v command can't be executed in DMs or group chats", command.Name))
 				return
 			}
+			args := fields[1:]
+			if command.RawArgs {
+				prefix := m.Content[:len(m.Content)-len(content)]
+				args = strings.Fields(trimPrefixFold(raw, prefix))[1:]
+			}
+
 			go func() {
 				log.Infof("Executing %v, requested by %v in %v", m.Content, m.Author.String(), where())
-				err := command.Exec(s, m, fields[1:])
-				handleError(s, m.ChannelID, err)
+				err := command.Exec(s, m, args)
+				handleError(s, m, err)
 			}()
 
 			break
@@ -118,7 +155,7 @@ func messageCreated(s *discordgo.Session, m *discordgo.MessageCreate) {
 }
 
 func reactCreated(s *discordgo.Session, r *discordgo.MessageReactionAdd) {
-	if guild, ok := database.GuildCache[r.GuildID]; ok {
+	if guild, ok := database.GuildCache.Lookup(r.GuildID); ok {
 		if !guild.Enabled || guild.StarboardChannel == "" {
 			return
 		}
@@ -128,6 +165,16 @@ func reactCreated(s *discordgo.Session, r *discordgo.MessageReactionAdd) {
 				return
 			}
 
+			// Removing the reaction triggers a recount, so there's nothing else to do.
+			if guild.RemoveBannedStars && guild.IsStarBanned(r.UserID) {
+				err := s.MessageReactionRemove(r.ChannelID, r.MessageID, r.Emoji.APIName(), r.UserID)
+				if err != nil {
+					logrus.Warnf("reactCreated() -> s.MessageReactionRemove(): %v", err)
+				} else {
+					return
+				}
+			}
+
 			msg, err := s.ChannelMessage(r.ChannelID, r.MessageID)
 			if err != nil {
 				logrus.Warnf("reactCreated() -> getMessage(): %v. Channel ID: %v, Message ID: %v", err, r.ChannelID, r.MessageID)
@@ -135,9 +182,12 @@ func reactCreated(s *discordgo.Session, r *discordgo.MessageReactionAdd) {
 			}
 
 			msg.GuildID = r.GuildID
+			if r.Member != nil {
+				memberCache.store(r.GuildID, r.UserID, r.Member)
+			}
 
 			if msg.Author != nil {
-				if msg.Author.ID == s.State.User.ID {
+				if msg.Author.ID == s.State.User.ID || isWebhookRepost(msg) {
 					return
 				}
 
@@ -145,7 +195,23 @@ func reactCreated(s *discordgo.Session, r *discordgo.MessageReactionAdd) {
 					return
 				}
 
-				if slices.Contains(guild.BlacklistedUsers, msg.Author.ID) {
+				if guild.IsBlacklisted(msg.Author.ID) {
+					return
+				}
+			}
+
+			// Stars are tracked before the star requirement is checked, so early stars of a burst aren't missed.
+			trackVote(guild, msg, r.UserID, true)
+
+			// Age limits only prevent new reposts, existing ones keep being updated.
+			if !guild.IsAgeAllowed(r.ChannelID, time.Since(msg.Timestamp)) {
+				repost, err := database.Repost(r.ChannelID, r.MessageID)
+				if err != nil {
+					logrus.Warnf("reactCreated() -> database.Repost(): %v", err)
+					return
+				}
+
+				if repost == nil {
 					return
 				}
 			}
@@ -157,7 +223,8 @@ func reactCreated(s *discordgo.Session, r *discordgo.MessageReactionAdd) {
 					return
 				}
 
-				if se.React.Count < guild.StarsRequired(se.message.ChannelID) {
+				// Weighted stars can exceed reaction count, so the requirement is compared with the most they can be worth.
+				if se.React.Count*guild.MaxVoteWeight() < guild.StarsRequired(se.message.ChannelID) {
 					return
 				}
 
@@ -169,7 +236,7 @@ func reactCreated(s *discordgo.Session, r *discordgo.MessageReactionAdd) {
 }
 
 func reactRemoved(s *discordgo.Session, r *discordgo.MessageReactionRemove) {
-	if guild, ok := database.GuildCache[r.GuildID]; ok {
+	if guild, ok := database.GuildCache.Lookup(r.GuildID); ok {
 		if !guild.Enabled || guild.StarboardChannel == "" {
 			return
 		}
@@ -186,7 +253,7 @@ func reactRemoved(s *discordgo.Session, r *discordgo.MessageReactionRemove) {
 			}
 
 			if msg.Author != nil {
-				if msg.Author.ID == s.State.User.ID {
+				if msg.Author.ID == s.State.User.ID || isWebhookRepost(msg) {
 					return
 				}
 
@@ -194,13 +261,13 @@ func reactRemoved(s *discordgo.Session, r *discordgo.MessageReactionRemove) {
 					return
 				}
 
-				for _, user := range guild.BlacklistedUsers {
-					if msg.Author.ID == user {
-						return
-					}
+				if guild.IsBlacklisted(msg.Author.ID) {
+					return
 				}
 			}
 
+			trackVote(guild, msg, r.UserID, false)
+
 			se, err := newStarboardEventRemove(s, r, msg)
 			if err != nil {
 				log.Warnln("newStarboardEventRemove():", err)
@@ -213,31 +280,34 @@ func reactRemoved(s *discordgo.Session, r *discordgo.MessageReactionRemove) {
 }
 
 func allReactsRemoved(s *discordgo.Session, r *discordgo.MessageReactionRemoveAll) {
-	guild, ok := database.GuildCache[r.GuildID]
+	guild, ok := database.GuildCache.Lookup(r.GuildID)
+	if !ok || !guild.Enabled || guild.StarboardChannel == "" || guild.IsBanned(r.ChannelID) {
+		return
+	}
+
 	msg, err := s.ChannelMessage(r.ChannelID, r.MessageID)
 	if err != nil {
 		logrus.Warnf("allReactsRemoved() -> s.ChannelMessage(): %v. Channel ID: %v, Message ID: %v", err, r.ChannelID, r.MessageID)
 		return
 	}
 
-	if ok && guild.Enabled && guild.StarboardChannel != "" && !guild.IsBanned(r.ChannelID) && msg.Author.ID != s.State.User.ID {
-		repost, err := database.Repost(r.ChannelID, r.MessageID)
-		if err != nil {
-			log.Warn(err)
-		}
+	if msg.Author != nil && msg.Author.ID == s.State.User.ID {
+		return
+	}
 
-		if repost != nil {
-			log.Infof("Removing starboard (all reactions removed) %v in channel %v", repost.Starboard.MessageID, repost.Starboard.ChannelID)
-			err := s.ChannelMessageDelete(repost.Starboard.ChannelID, repost.Starboard.MessageID)
-			if err != nil {
-				log.Warnln("allReactsRemoved() -> s.ChannelMessageDelete(): ", err)
-			}
-		}
+	// The event is queued like a star removal, so trashed, frozen and forced posts are handled the same way.
+	se, err := newStarboardEventCleared(s, r, msg)
+	if err != nil {
+		log.Warnln("newStarboardEventCleared():", err)
+		return
 	}
+
+	p := database.NewPair(r.ChannelID, r.MessageID)
+	starboardQueue.Push(p, se)
 }
 
 func messageDeleted(s *discordgo.Session, m *discordgo.MessageDelete) {
-	guild, ok := database.GuildCache[m.GuildID]
+	guild, ok := database.GuildCache.Lookup(m.GuildID)
 
 	if ok && guild.Enabled && guild.StarboardChannel != "" && !guild.IsBanned(m.ChannelID) {
 		se, err := newStarboardEventDeleted(s, m)
@@ -251,18 +321,18 @@ func messageDeleted(s *discordgo.Session, m *discordgo.MessageDelete) {
 }
 
 func guildCreated(s *discordgo.Session, g *discordgo.GuildCreate) {
-	if len(database.GuildCache) == 0 {
+	if database.GuildCache.Len() == 0 {
 		return
 	}
 
-	if _, ok := database.GuildCache[g.ID]; !ok {
+	if _, ok := database.GuildCache.Lookup(g.ID); !ok {
 		newGuild := database.NewGuild(g.Name, g.ID)
 		err := database.InsertOneGuild(newGuild)
 		if err != nil {
 			log.Println(err)
 		}
 
-		database.GuildCache[g.ID] = newGuild
+		database.GuildCache.Set(g.ID, newGuild)
 		log.Infoln("Joined ", g.Name)
 	}
 }
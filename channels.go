@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/VTGare/Eugen/database"
+	"github.com/VTGare/Eugen/utils"
+	"github.com/bwmarrin/discordgo"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	channelCacheTTL = time.Hour
+	// channelMissTTL is how long channels that couldn't be resolved are treated as having no parent.
+	channelMissTTL = 5 * time.Minute
+	// channelCacheSweep is a number of cached channels after which expired entries are swept.
+	channelCacheSweep = 10000
+)
+
+var (
+	channelParents = &parents{entries: make(map[string]*cachedParent)}
+)
+
+type parents struct {
+	mu      sync.Mutex
+	entries map[string]*cachedParent
+}
+
+type cachedParent struct {
+	parentID string
+	expires  time.Time
+}
+
+// parent returns an ID of a category or a channel that contains a channel, resolving it from state or API.
+func (p *parents) parent(s *discordgo.Session, channelID string) string {
+	p.mu.Lock()
+	cached, ok := p.entries[channelID]
+	p.mu.Unlock()
+
+	if ok && time.Now().Before(cached.expires) {
+		return cached.parentID
+	}
+
+	ch, err := s.State.Channel(channelID)
+	if err != nil {
+		ch, err = s.Channel(channelID)
+		if err != nil {
+			logrus.Debugf("channelParents.parent(): %v", err)
+			p.set(channelID, &cachedParent{expires: time.Now().Add(channelMissTTL)})
+			return ""
+		}
+	}
+
+	p.store(ch)
+	return ch.ParentID
+}
+
+func (p *parents) store(ch *discordgo.Channel) {
+	p.set(ch.ID, &cachedParent{parentID: ch.ParentID, expires: time.Now().Add(channelCacheTTL)})
+}
+
+func (p *parents) set(channelID string, cached *cachedParent) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.entries) > channelCacheSweep {
+		for id, c := range p.entries {
+			if time.Now().After(c.expires) {
+				delete(p.entries, id)
+			}
+		}
+	}
+
+	p.entries[channelID] = cached
+}
+
+// channelParent implements database.ChannelParent.
+func channelParent(channelID string) string {
+	return channelParents.parent(dg, channelID)
+}
+
+// stateChannel returns a channel from state, fetching it if it isn't cached.
+func stateChannel(s *discordgo.Session, channelID string) (*discordgo.Channel, error) {
+	ch, err := s.State.Channel(channelID)
+	if err != nil {
+		return s.Channel(channelID)
+	}
+
+	return ch, nil
+}
+
+// channelLabel returns a channel name shown in reposts. Threads and forum posts are prefixed with their parent's name.
+func channelLabel(s *discordgo.Session, ch *discordgo.Channel) string {
+	if !ch.IsThread() {
+		return ch.Name
+	}
+
+	parent, err := stateChannel(s, ch.ParentID)
+	if err != nil {
+		logrus.Debugf("channelLabel(): %v", err)
+		return ch.Name
+	}
+
+	return parent.Name + " › " + ch.Name
+}
+
+// isVoiceChannel reports whether a channel is a voice or stage channel, whose text chat is labelled with a speaker.
+func isVoiceChannel(ch *discordgo.Channel) bool {
+	return ch.Type == discordgo.ChannelTypeGuildVoice || ch.Type == discordgo.ChannelTypeGuildStageVoice
+}
+
+// isAgeRestricted reports whether a channel is NSFW. Threads are age-restricted if their parent channel is.
+func isAgeRestricted(s *discordgo.Session, channelID string) (bool, error) {
+	ch, err := stateChannel(s, channelID)
+	if err != nil {
+		return false, err
+	}
+
+	if ch.NSFW || !ch.IsThread() {
+		return ch.NSFW, nil
+	}
+
+	return isAgeRestricted(s, ch.ParentID)
+}
+
+// channelUpdated keeps cached parents up to date when channels are moved between categories
+// and warns when a starboard that can receive NSFW reposts stops being age-restricted.
+func channelUpdated(s *discordgo.Session, c *discordgo.ChannelUpdate) {
+	channelParents.store(c.Channel)
+
+	// Only changes of age restriction are reported, so unknown previous state is skipped.
+	if c.BeforeUpdate == nil || !c.BeforeUpdate.NSFW || c.NSFW {
+		return
+	}
+
+	guild, ok := database.GuildCache.Lookup(c.GuildID)
+	if !ok {
+		return
+	}
+
+	var details string
+	switch {
+	case guild.NSFWStarboardChannel == c.ID:
+		details = fmt.Sprintf("NSFW starboard <#%v> isn't age-restricted anymore. Messages from age-restricted channels won't be reposted until it is.", c.ID)
+	case guild.IsDestination(c.ID):
+		details = fmt.Sprintf("Starboard <#%v> isn't age-restricted anymore. Messages from age-restricted channels routed to it won't be reposted until it is.", c.ID)
+	default:
+		return
+	}
+
+	utils.LogAction(s, &utils.LogEntry{
+		Action:  utils.ActionRefused,
+		GuildID: c.GuildID,
+		Details: details,
+	})
+}
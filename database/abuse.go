@@ -0,0 +1,33 @@
+package database
+
+import (
+	"time"
+)
+
+// AntiAbuse configures heuristics that discount suspicious stars. Zero values disable a check.
+type AntiAbuse struct {
+	// MinAccountAge and MinMemberAge are minimum age of starrer's account and membership in the server.
+	MinAccountAge time.Duration `json:"min_account_age" bson:"min_account_age"`
+	MinMemberAge  time.Duration `json:"min_member_age" bson:"min_member_age"`
+	// DailyAuthorCap is how many posts of one author a user can star per day.
+	DailyAuthorCap int `json:"daily_author_cap" bson:"daily_author_cap"`
+	// BurstSize stars from freshly joined members within BurstWindow are considered coordinated.
+	BurstSize   int           `json:"burst_size" bson:"burst_size"`
+	BurstWindow time.Duration `json:"burst_window" bson:"burst_window"`
+}
+
+// HasAntiAbuse reports whether any anti-abuse check is enabled.
+func (g *Guild) HasAntiAbuse() bool {
+	a := g.AntiAbuse
+	return a != nil && (a.MinAccountAge > 0 || a.MinMemberAge > 0 || a.DailyAuthorCap > 0 || (a.BurstSize > 0 && a.BurstWindow > 0))
+}
+
+// Copy returns a copy of anti-abuse settings. Nil is copied as disabled settings.
+func (a *AntiAbuse) Copy() *AntiAbuse {
+	if a == nil {
+		return &AntiAbuse{}
+	}
+
+	c := *a
+	return &c
+}
@@ -0,0 +1,156 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrAuditNotFound = errors.New("audit entry not found")
+	// ErrAuditOutdated is returned when a setting was changed again after an audited change.
+	ErrAuditOutdated = errors.New("setting was changed after this entry, roll back newer changes first")
+
+	// auditIgnored are guild fields that aren't settings.
+	auditIgnored = map[string]bool{"_id": true, "guild_id": true, "name": true, "created_at": true, "updated_at": true}
+)
+
+// AuditEntry is a single change of a guild setting. Values are stored as raw BSON of the guild field.
+type AuditEntry struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	GuildID   string             `bson:"guild_id" json:"guild_id"`
+	ActorID   string             `bson:"actor_id" json:"actor_id"`
+	Field     string             `bson:"field" json:"field"`
+	OldValue  bson.RawValue      `bson:"old_value" json:"old_value"`
+	NewValue  bson.RawValue      `bson:"new_value" json:"new_value"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// RecordChanges compares two versions of guild settings and stores an audit entry for each changed field.
+func RecordChanges(actorID string, before, after *Guild) error {
+	if before == nil || after == nil {
+		return nil
+	}
+
+	oldRaw, err := bson.Marshal(before)
+	if err != nil {
+		return err
+	}
+
+	newRaw, err := bson.Marshal(after)
+	if err != nil {
+		return err
+	}
+
+	elements, err := bson.Raw(newRaw).Elements()
+	if err != nil {
+		return err
+	}
+
+	entries := make([]interface{}, 0)
+	for _, el := range elements {
+		field := el.Key()
+		if auditIgnored[field] {
+			continue
+		}
+
+		oldValue := bson.Raw(oldRaw).Lookup(field)
+		newValue := el.Value()
+		if oldValue.Equal(newValue) {
+			continue
+		}
+
+		entries = append(entries, &AuditEntry{
+			GuildID:   after.ID,
+			ActorID:   actorID,
+			Field:     field,
+			OldValue:  oldValue,
+			NewValue:  newValue,
+			CreatedAt: time.Now(),
+		})
+	}
+
+	if len(entries) == 0 {
+		return nil
+	}
+
+	_, err = DB.Collection("guild_audit").InsertMany(context.Background(), entries)
+	return err
+}
+
+// AuditHistory returns guild's settings changes, newest first.
+func AuditHistory(guildID string, skip, limit int64) ([]*AuditEntry, error) {
+	col := DB.Collection("guild_audit")
+	cur, err := col.Find(context.Background(), bson.M{"guild_id": guildID}, options.Find().SetSort(bson.M{"_id": -1}).SetSkip(skip).SetLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*AuditEntry, 0)
+	if err := cur.All(context.Background(), &entries); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func AuditByID(guildID, id string) (*AuditEntry, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrAuditNotFound
+	}
+
+	res := DB.Collection("guild_audit").FindOne(context.Background(), bson.M{"_id": oid, "guild_id": guildID})
+	if err := res.Err(); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrAuditNotFound
+		}
+		return nil, err
+	}
+
+	entry := &AuditEntry{}
+	if err := res.Decode(entry); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// Rollback restores an old value of an audited setting. The rollback is audited too.
+func Rollback(entry *AuditEntry, actorID string) error {
+	before, ok := GuildCache.Lookup(entry.GuildID)
+	if !ok {
+		return errors.New("unknown guild")
+	}
+
+	raw, err := bson.Marshal(before)
+	if err != nil {
+		return err
+	}
+
+	if !bson.Raw(raw).Lookup(entry.Field).Equal(entry.NewValue) {
+		return ErrAuditOutdated
+	}
+
+	res := DB.Collection("guilds").FindOneAndUpdate(context.Background(), bson.M{
+		"guild_id": entry.GuildID,
+	}, bson.M{
+		"$set": bson.M{
+			entry.Field:  entry.OldValue,
+			"updated_at": time.Now(),
+		},
+	}, options.FindOneAndUpdate().SetReturnDocument(options.After))
+
+	guild := &Guild{}
+	if err := res.Decode(guild); err != nil {
+		return err
+	}
+
+	GuildCache.Set(entry.GuildID, guild)
+	return RecordChanges(actorID, before, guild)
+}
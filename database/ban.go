@@ -0,0 +1,146 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+)
+
+// Ban is an entry of banned channels or blacklisted users. Bans without expiry date are permanent.
+type Ban struct {
+	ID        string    `json:"id" bson:"id"`
+	Reason    string    `json:"reason,omitempty" bson:"reason,omitempty"`
+	ActorID   string    `json:"actor_id,omitempty" bson:"actor_id,omitempty"`
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+	ExpiresAt time.Time `json:"expires_at,omitempty" bson:"expires_at,omitempty"`
+}
+
+// ban has the same layout as Ban without its BSON unmarshaler.
+type ban Ban
+
+// UnmarshalBSONValue decodes a ban, including plain IDs stored by older versions.
+func (b *Ban) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	if t == bsontype.String {
+		id, _, ok := bsoncore.ReadString(data)
+		if !ok {
+			return fmt.Errorf("invalid ban %v", data)
+		}
+
+		*b = Ban{ID: id}
+		return nil
+	}
+
+	return bson.Unmarshal(data, (*ban)(b))
+}
+
+// Expired reports whether a temporary ban should be lifted.
+func (b *Ban) Expired() bool {
+	return !b.ExpiresAt.IsZero() && time.Now().After(b.ExpiresAt)
+}
+
+// String returns ban's expiry and reason, e.g. "expires in 3 days, spamming".
+func (b *Ban) String() string {
+	details := make([]string, 0, 2)
+	if !b.ExpiresAt.IsZero() {
+		details = append(details, fmt.Sprintf("expires <t:%v:R>", b.ExpiresAt.Unix()))
+	}
+
+	if b.Reason != "" {
+		details = append(details, b.Reason)
+	}
+
+	return strings.Join(details, ", ")
+}
+
+func findBan(bans []*Ban, id string) *Ban {
+	for _, b := range bans {
+		if b.ID == id && !b.Expired() {
+			return b
+		}
+	}
+
+	return nil
+}
+
+// addBan adds a ban or replaces an existing ban of the same ID, field is "banned", "blacklisted_users" or "star_banned".
+func addBan(guildID, field string, b *Ban) error {
+	if err := removeBan(guildID, field, b.ID); err != nil {
+		return err
+	}
+
+	return updateBans(guildID, bson.M{
+		"$push": bson.M{field: b},
+	})
+}
+
+// removeBan removes bans of given ID, including plain IDs stored by older versions.
+func removeBan(guildID, field, id string) error {
+	if err := updateBans(guildID, bson.M{
+		"$pull": bson.M{field: id},
+	}); err != nil {
+		return err
+	}
+
+	return updateBans(guildID, bson.M{
+		"$pull": bson.M{field: bson.M{"id": id}},
+	})
+}
+
+// updateBans applies an update to a ban list and caches the result. Only the given bans are changed,
+// so bans added or lifted concurrently aren't overwritten.
+func updateBans(guildID string, update bson.M) error {
+	col := DB.Collection("guilds")
+
+	update["$set"] = bson.M{"updated_at": time.Now()}
+	res := col.FindOneAndUpdate(context.Background(), bson.M{
+		"guild_id": guildID,
+	}, update, options.FindOneAndUpdate().SetReturnDocument(options.After))
+
+	guild := &Guild{}
+	err := res.Decode(guild)
+	if err != nil {
+		return err
+	}
+
+	GuildCache.Set(guildID, guild)
+	return nil
+}
+
+// LiftExpiredBans removes expired temporary bans from all guilds. It's run periodically by the bot.
+func LiftExpiredBans() {
+	for _, guild := range GuildCache.All() {
+		for field, bans := range map[string][]*Ban{"banned": guild.BannedChannels, "blacklisted_users": guild.BlacklistedUsers, "star_banned": guild.StarBannedUsers} {
+			expired := false
+			for _, b := range bans {
+				if b.Expired() {
+					expired = true
+					break
+				}
+			}
+
+			if !expired {
+				continue
+			}
+
+			// Expiry is checked by the update itself, so bans issued since the snapshot are kept.
+			before := GuildCache.Get(guild.ID)
+			if err := updateBans(guild.ID, bson.M{
+				"$pull": bson.M{field: bson.M{"expires_at": bson.M{"$lte": time.Now()}}},
+			}); err != nil {
+				log.Printf("LiftExpiredBans(): %v", err)
+				continue
+			}
+
+			if err := RecordChanges("", before, GuildCache.Get(guild.ID)); err != nil {
+				log.Printf("RecordChanges(): %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package database
+
+import "sync"
+
+// guildCache holds guild settings. It's shared by event handlers, commands and scheduled jobs, so it's guarded by a mutex.
+type guildCache struct {
+	mu     sync.RWMutex
+	guilds map[string]*Guild
+}
+
+// Get returns cached guild settings or nil.
+func (c *guildCache) Get(guildID string) *Guild {
+	guild, _ := c.Lookup(guildID)
+	return guild
+}
+
+// Lookup returns cached guild settings and reports whether they were found.
+func (c *guildCache) Lookup(guildID string) (*Guild, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	guild, ok := c.guilds[guildID]
+	return guild, ok
+}
+
+func (c *guildCache) Set(guildID string, guild *Guild) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.guilds[guildID] = guild
+}
+
+func (c *guildCache) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return len(c.guilds)
+}
+
+// All returns a snapshot of cached guilds that's safe to iterate while the cache is updated.
+func (c *guildCache) All() []*Guild {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	guilds := make([]*Guild, 0, len(c.guilds))
+	for _, guild := range c.guilds {
+		guilds = append(guilds, guild)
+	}
+
+	return guilds
+}
@@ -0,0 +1,40 @@
+package database
+
+// ChannelParent returns a parent of a channel, i.e. category of a channel or channel of a thread, or an empty string.
+// It's provided by the bot on start up.
+var ChannelParent func(channelID string) string
+
+// maxChannelDepth is the deepest channel hierarchy: category, channel, thread.
+const maxChannelDepth = 3
+
+// lineage returns a channel followed by its parents, from the closest to the furthest one.
+func lineage(channelID string) []string {
+	ids := []string{channelID}
+	if ChannelParent == nil || channelID == "" {
+		return ids
+	}
+
+	for len(ids) < maxChannelDepth {
+		parent := ChannelParent(ids[len(ids)-1])
+		if parent == "" {
+			break
+		}
+
+		ids = append(ids, parent)
+	}
+
+	return ids
+}
+
+// channelSettings returns the closest settings in channel's lineage that satisfy has or nil.
+func (g *Guild) channelSettings(channelID string, has func(*ChannelSettings) bool) *ChannelSettings {
+	for _, id := range lineage(channelID) {
+		for _, ch := range g.ChannelSettings {
+			if ch.ID == id && has(ch) {
+				return ch
+			}
+		}
+	}
+
+	return nil
+}
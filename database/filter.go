@@ -0,0 +1,149 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+const (
+	FilterSubstring = "substring"
+	FilterWord      = "word"
+	FilterRegex     = "regex"
+	FilterInvite    = "invite"
+	FilterMentions  = "mentions"
+
+	// defaultMentionLimit is a number of mentions considered mass mentioning if filter doesn't specify it.
+	defaultMentionLimit = 5
+	// maxFilterPattern is the longest pattern allowed in a filter.
+	maxFilterPattern = 256
+	// listedFilterPattern is the longest pattern shown in a list of filters, so the whole list fits in an embed.
+	listedFilterPattern = 60
+)
+
+var (
+	inviteRegex = regexp.MustCompile(`(?i)(discord\.gg|discord(app)?\.com/invite)/[\w-]+`)
+
+	filterRegexes   = make(map[string]*regexp.Regexp)
+	filterRegexesMu sync.Mutex
+)
+
+// ContentFilter prevents messages that match it from being starboarded.
+type ContentFilter struct {
+	Kind    string `json:"kind" bson:"kind"`
+	Pattern string `json:"pattern,omitempty" bson:"pattern,omitempty"`
+	// Limit is the number of mentions that triggers mass mention filter.
+	Limit int `json:"limit,omitempty" bson:"limit,omitempty"`
+}
+
+// NewContentFilter validates filter's pattern.
+func NewContentFilter(kind, pattern string) (*ContentFilter, error) {
+	if len(pattern) > maxFilterPattern {
+		return nil, fmt.Errorf("filter pattern is too long, maximum is %v characters", maxFilterPattern)
+	}
+
+	f := &ContentFilter{Kind: kind, Pattern: pattern}
+	switch kind {
+	case FilterSubstring, FilterWord:
+		if pattern == "" {
+			return nil, errors.New("filter pattern is required")
+		}
+	case FilterRegex:
+		if _, err := compileFilter(pattern); err != nil {
+			return nil, fmt.Errorf("invalid regular expression: %w", err)
+		}
+	case FilterInvite:
+		f.Pattern = ""
+	case FilterMentions:
+		f.Pattern, f.Limit = "", defaultMentionLimit
+		if pattern != "" {
+			if _, err := fmt.Sscan(pattern, &f.Limit); err != nil || f.Limit < 1 {
+				return nil, errors.New("mention limit should be a number greater than 0")
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unknown filter %v", kind)
+	}
+
+	return f, nil
+}
+
+// Match reports whether content or number of mentions trigger the filter.
+func (f *ContentFilter) Match(content string, mentions int) bool {
+	switch f.Kind {
+	case FilterSubstring:
+		return strings.Contains(strings.ToLower(content), strings.ToLower(f.Pattern))
+	case FilterWord:
+		re, err := compileFilter(`(?i)\b` + regexp.QuoteMeta(f.Pattern) + `\b`)
+		return err == nil && re.MatchString(content)
+	case FilterRegex:
+		re, err := compileFilter(f.Pattern)
+		return err == nil && re.MatchString(content)
+	case FilterInvite:
+		return inviteRegex.MatchString(content)
+	case FilterMentions:
+		return mentions >= f.Limit
+	}
+
+	return false
+}
+
+func (f *ContentFilter) String() string {
+	switch f.Kind {
+	case FilterInvite:
+		return "invite links"
+	case FilterMentions:
+		return fmt.Sprintf("%v or more mentions", f.Limit)
+	}
+
+	return fmt.Sprintf("%v ``%v``", f.Kind, f.Pattern)
+}
+
+// MatchFilter returns the first filter that matches content or nil.
+func (g *Guild) MatchFilter(content string, mentions int) *ContentFilter {
+	for _, f := range g.Filters {
+		if f.Match(content, mentions) {
+			return f
+		}
+	}
+
+	return nil
+}
+
+func (g *Guild) FiltersToString() string {
+	if len(g.Filters) == 0 {
+		return "none"
+	}
+
+	lines := make([]string, 0, len(g.Filters))
+	for i, f := range g.Filters {
+		listed := *f
+		if runes := []rune(listed.Pattern); len(runes) > listedFilterPattern {
+			listed.Pattern = string(runes[:listedFilterPattern-1]) + "…"
+		}
+
+		lines = append(lines, fmt.Sprintf("%v. %v", i+1, &listed))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// compileFilter compiles a regular expression once and caches it.
+func compileFilter(pattern string) (*regexp.Regexp, error) {
+	filterRegexesMu.Lock()
+	defer filterRegexesMu.Unlock()
+
+	if re, ok := filterRegexes[pattern]; ok {
+		return re, nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	filterRegexes[pattern] = re
+	return re, nil
+}
@@ -0,0 +1,55 @@
+package database
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewContentFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		kind    string
+		pattern string
+		want    *ContentFilter
+		wantErr bool
+	}{
+		{"substring", FilterSubstring, "spoiler", &ContentFilter{Kind: FilterSubstring, Pattern: "spoiler"}, false},
+		{"empty word", FilterWord, "", nil, true},
+		{"regex", FilterRegex, `^\d+$`, &ContentFilter{Kind: FilterRegex, Pattern: `^\d+$`}, false},
+		{"invalid regex", FilterRegex, `(`, nil, true},
+		{"invite ignores pattern", FilterInvite, "anything", &ContentFilter{Kind: FilterInvite}, false},
+		{"default mention limit", FilterMentions, "", &ContentFilter{Kind: FilterMentions, Limit: defaultMentionLimit}, false},
+		{"mention limit", FilterMentions, "10", &ContentFilter{Kind: FilterMentions, Limit: 10}, false},
+		{"zero mention limit", FilterMentions, "0", nil, true},
+		{"unknown kind", "emoji", "x", nil, true},
+		{"long pattern", FilterSubstring, strings.Repeat("a", maxFilterPattern+1), nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewContentFilter(tt.kind, tt.pattern)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewContentFilter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if *got != *tt.want {
+				t.Errorf("NewContentFilter() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewContentFilterLongRegexNotCached(t *testing.T) {
+	pattern := strings.Repeat("a", maxFilterPattern+1)
+	if _, err := NewContentFilter(FilterRegex, pattern); err == nil {
+		t.Fatal("NewContentFilter() accepted a pattern over the limit")
+	}
+
+	if _, ok := filterRegexes[pattern]; ok {
+		t.Error("NewContentFilter() cached a rejected pattern")
+	}
+}
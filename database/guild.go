@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strconv"
 	"strings"
 	"time"
 
@@ -22,45 +23,143 @@ type Guild struct {
 	Enabled              bool               `json:"enabled" bson:"enabled"`
 	StarboardChannel     string             `json:"starboard" bson:"starboard"`
 	NSFWStarboardChannel string             `json:"nsfwstarboard" bson:"nsfwstarboard"`
+	HallOfFameChannel    string             `json:"halloffame" bson:"halloffame"`
+	HallOfFameStars      int                `json:"halloffamestars" bson:"halloffamestars"`
+	LogChannel           string             `json:"logchannel" bson:"logchannel"`
 	Selfstar             bool               `json:"selfstar" bson:"selfstar"`
 	IgnoreBots           bool               `json:"ignorebots" bson:"ignorebots"`
+	WebhookMode          bool               `json:"webhook" bson:"webhook"`
 	MinimumStars         int                `json:"stars" bson:"stars"`
+	Unstar               *UnstarPolicy      `json:"unstar" bson:"unstar"`
+	MaxMessageAge        time.Duration      `json:"maxage" bson:"maxage"`
+	MinMessageAge        time.Duration      `json:"minage" bson:"minage"`
+	Template             *EmbedTemplate     `json:"template" bson:"template"`
+	Tiers                []*StarTier        `json:"tiers" bson:"tiers"`
+	StarRoles            *StarRoles         `json:"starroles" bson:"starroles"`
+	AntiAbuse            *AntiAbuse         `json:"antiabuse" bson:"antiabuse"`
+	Filters              []*ContentFilter   `json:"filters" bson:"filters"`
+	FilterReport         bool               `json:"filterreport" bson:"filterreport"`
+	PlainMentions        bool               `json:"plainmentions" bson:"plainmentions"`
 	ChannelSettings      []*ChannelSettings `json:"channel_settings" bson:"channel_settings"`
-	BlacklistedUsers     []string           `json:"blacklisted_users" bson:"blacklisted_users"`
-	BannedChannels       []string           `json:"banned" bson:"banned"`
+	BlacklistedUsers     []*Ban             `json:"blacklisted_users" bson:"blacklisted_users"`
+	BannedChannels       []*Ban             `json:"banned" bson:"banned"`
+	StarBannedUsers      []*Ban             `json:"star_banned" bson:"star_banned"`
+	RemoveBannedStars    bool               `json:"removebannedstars" bson:"removebannedstars"`
 	CreatedAt            time.Time          `json:"created_at" bson:"created_at"`
 	UpdatedAt            time.Time          `json:"updated_at" bson:"updated_at"`
 }
 
 type ChannelSettings struct {
-	ID              string `json:"id" bson:"id"`
-	StarRequirement int    `json:"star_requirement" bson:"star_requirement"`
+	ID              string        `json:"id" bson:"id"`
+	StarRequirement int           `json:"star_requirement" bson:"star_requirement"`
+	Unstar          *UnstarPolicy `json:"unstar,omitempty" bson:"unstar,omitempty"`
+	// MaxAge and MinAge override guild's message age limits, zero disables a limit.
+	MaxAge *time.Duration `json:"max_age,omitempty" bson:"max_age,omitempty"`
+	MinAge *time.Duration `json:"min_age,omitempty" bson:"min_age,omitempty"`
+	// Starboard and Colour route channel's reposts to its own starboard with its own embed colour. Empty and zero values use guild's ones.
+	Starboard string `json:"starboard,omitempty" bson:"starboard,omitempty"`
+	Colour    int64  `json:"color,omitempty" bson:"color,omitempty"`
 }
 
-func (g *Guild) StarsRequired(channelID string) int {
-	for _, ch := range g.ChannelSettings {
-		if ch.ID == channelID {
-			return ch.StarRequirement
+// IsDestination reports whether reposts of any channel are routed to a starboard channel.
+func (g *Guild) IsDestination(starboardID string) bool {
+	for _, cs := range g.ChannelSettings {
+		if cs.Starboard == starboardID {
+			return true
 		}
 	}
+
+	return false
+}
+
+// Destination returns a starboard channel for reposts from a channel. Channel rules take precedence over NSFW starboard.
+func (g *Guild) Destination(channelID string, nsfw bool) string {
+	if cs := g.channelSettings(channelID, func(cs *ChannelSettings) bool { return cs.Starboard != "" }); cs != nil {
+		return cs.Starboard
+	}
+
+	if nsfw && g.NSFWStarboardChannel != "" {
+		return g.NSFWStarboardChannel
+	}
+
+	return g.StarboardChannel
+}
+
+// StarsRequired returns star requirement of a channel. Threads and channels inherit requirements of their parents.
+func (g *Guild) StarsRequired(channelID string) int {
+	cs := g.channelSettings(channelID, func(cs *ChannelSettings) bool { return cs.StarRequirement > 0 })
+	if cs != nil {
+		return cs.StarRequirement
+	}
 	return g.MinimumStars
 }
 
+func (cs *ChannelSettings) String() string {
+	str := fmt.Sprintf("<#%v>``%v``: ", cs.ID, cs.ID)
+	if cs.StarRequirement > 0 {
+		str += strconv.Itoa(cs.StarRequirement)
+	} else {
+		str += "default"
+	}
+
+	if cs.Unstar != nil {
+		str += fmt.Sprintf(" (unstar %v)", cs.Unstar)
+	}
+
+	if cs.Starboard != "" {
+		str += fmt.Sprintf(" → <#%v>", cs.Starboard)
+	}
+
+	if cs.Colour != 0 {
+		str += fmt.Sprintf(" (color %v)", cs.Colour)
+	}
+
+	return str
+}
+
+// MessageAgeLimits returns minimum and maximum age of a message that can be starboarded. Zero means no limit.
+func (g *Guild) MessageAgeLimits(channelID string) (time.Duration, time.Duration) {
+	min, max := g.MinMessageAge, g.MaxMessageAge
+	if cs := g.channelSettings(channelID, func(cs *ChannelSettings) bool { return cs.MinAge != nil }); cs != nil {
+		min = *cs.MinAge
+	}
+
+	if cs := g.channelSettings(channelID, func(cs *ChannelSettings) bool { return cs.MaxAge != nil }); cs != nil {
+		max = *cs.MaxAge
+	}
+
+	return min, max
+}
+
+// IsAgeAllowed reports whether a message of given age can be starboarded.
+func (g *Guild) IsAgeAllowed(channelID string, age time.Duration) bool {
+	min, max := g.MessageAgeLimits(channelID)
+	if max > 0 && age > max {
+		return false
+	}
+
+	if min > 0 && age < min {
+		return false
+	}
+
+	return true
+}
+
 func (g *Guild) ChannelSettingsToString() string {
 	var sb strings.Builder
 	if len(g.ChannelSettings) == 0 {
 		return "none"
 	}
 
-	sb.WriteString(fmt.Sprintf("<#%v>``%v``: %v ", g.ChannelSettings[0].ID, g.ChannelSettings[0].ID, g.ChannelSettings[0].StarRequirement))
+	sb.WriteString(g.ChannelSettings[0].String() + " ")
 	inRow := 1
 	if len(g.ChannelSettings) > 1 {
 		for _, ch := range g.ChannelSettings[1:] {
 			if inRow == 2 {
-				sb.WriteString(fmt.Sprintf("\n<#%v>``%v``: %v ", ch.ID, ch.ID, ch.StarRequirement))
+				sb.WriteString("\n" + ch.String() + " ")
 				inRow = 0
 			} else {
-				sb.WriteString(fmt.Sprintf("| <#%v>``%v``: %v ", ch.ID, ch.ID, ch.StarRequirement))
+				sb.WriteString("| " + ch.String() + " ")
 			}
 			inRow++
 		}
@@ -70,51 +169,73 @@ func (g *Guild) ChannelSettingsToString() string {
 }
 
 func (g *Guild) BannedChannelsToString() string {
-	var sb strings.Builder
-	if len(g.BannedChannels) == 0 {
-		return "none"
-	}
+	return bansToString(g.BannedChannels, "<#%v>``%v``")
+}
 
-	sb.WriteString(fmt.Sprintf("<#%v>``%v`` ", g.BannedChannels[0], g.BannedChannels[0]))
-	inRow := 1
+func (g *Guild) BlacklistedToString() string {
+	return bansToString(g.BlacklistedUsers, "<@%v>``%v``")
+}
 
-	if len(g.BannedChannels) > 1 {
-		for _, ch := range g.BannedChannels[1:] {
-			if inRow == 2 {
-				sb.WriteString(fmt.Sprintf("\n<#%v>``%v`` ", ch, ch))
-				inRow = 0
-			} else {
-				sb.WriteString(fmt.Sprintf("| <#%v>``%v``", ch, ch))
-			}
-			inRow++
+func bansToString(bans []*Ban, format string) string {
+	lines := make([]string, 0, len(bans))
+	for _, b := range bans {
+		if b.Expired() {
+			continue
 		}
-	}
 
-	return sb.String()
-}
+		line := fmt.Sprintf(format, b.ID, b.ID)
+		if details := b.String(); details != "" {
+			line += " " + details
+		}
 
-func (g *Guild) BlacklistedToString() string {
-	var sb strings.Builder
-	if len(g.BlacklistedUsers) == 0 {
+		lines = append(lines, line)
+	}
+
+	if len(lines) == 0 {
 		return "none"
 	}
 
-	sb.WriteString(fmt.Sprintf("<@%v>", g.BlacklistedUsers[0]))
-	if len(g.ChannelSettings) > 1 {
-		for _, user := range g.BlacklistedUsers[1:] {
-			sb.WriteString(fmt.Sprintf("| <@%v>", user))
+	return strings.Join(lines, "\n")
+}
+
+// IsBanned reports whether a channel or any of its parents is banned.
+func (g *Guild) IsBanned(channelID string) bool {
+	for _, id := range lineage(channelID) {
+		if g.ChannelBan(id) != nil {
+			return true
 		}
 	}
 
-	return sb.String()
+	return false
 }
 
-func (g *Guild) IsBanned(channelID string) bool {
-	for _, id := range g.BannedChannels {
-		if id == channelID {
+// ChannelBan returns an active ban of exactly this channel or nil.
+func (g *Guild) ChannelBan(channelID string) *Ban {
+	return findBan(g.BannedChannels, channelID)
+}
+
+// IsBlacklisted reports whether user's messages can't be starboarded.
+func (g *Guild) IsBlacklisted(userID string) bool {
+	return findBan(g.BlacklistedUsers, userID) != nil
+}
+
+func (g *Guild) StarBannedToString() string {
+	return bansToString(g.StarBannedUsers, "<@%v>``%v``")
+}
+
+// IsStarBanned reports whether user's stars are ignored.
+func (g *Guild) IsStarBanned(userID string) bool {
+	return findBan(g.StarBannedUsers, userID) != nil
+}
+
+// HasStarBans reports whether any user is banned from starring.
+func (g *Guild) HasStarBans() bool {
+	for _, b := range g.StarBannedUsers {
+		if !b.Expired() {
 			return true
 		}
 	}
+
 	return false
 }
 
@@ -126,6 +247,23 @@ func (g *Guild) IsGuildEmoji() bool {
 	return strings.HasPrefix(g.StarEmote, "<:")
 }
 
+// Promotion is a board starboard posts are cross-posted to once they reach a higher threshold.
+type Promotion struct {
+	Board     string
+	ChannelID string
+	Threshold int
+}
+
+// Promotions returns boards configured in addition to the starboard.
+func (g *Guild) Promotions() []*Promotion {
+	promotions := make([]*Promotion, 0)
+	if g.HallOfFameChannel != "" && g.HallOfFameStars > 0 {
+		promotions = append(promotions, &Promotion{BoardHallOfFame, g.HallOfFameChannel, g.HallOfFameStars})
+	}
+
+	return promotions
+}
+
 func NewGuild(guildName, guildID string) *Guild {
 	return &Guild{
 		Prefix:               "e!",
@@ -139,9 +277,10 @@ func NewGuild(guildName, guildID string) *Guild {
 		EmbedColour:          4431601,
 		StarboardChannel:     "",
 		NSFWStarboardChannel: "",
-		BlacklistedUsers:     make([]string, 0),
+		BlacklistedUsers:     make([]*Ban, 0),
 		ChannelSettings:      make([]*ChannelSettings, 0),
-		BannedChannels:       make([]string, 0),
+		BannedChannels:       make([]*Ban, 0),
+		StarBannedUsers:      make([]*Ban, 0),
 		CreatedAt:            time.Now(),
 		UpdatedAt:            time.Now(),
 	}
@@ -207,120 +346,100 @@ func RemoveGuild(guildID string) error {
 	return nil
 }
 
-func BanChannel(guildID, channelID string) error {
-	col := DB.Collection("guilds")
-
-	res := col.FindOneAndUpdate(context.Background(), bson.M{
-		"guild_id": guildID,
-	}, bson.M{
-		"$set": bson.M{
-			"updated_at": time.Now(),
-		},
-		"$addToSet": bson.M{
-			"banned": channelID,
-		},
-	}, options.FindOneAndUpdate().SetReturnDocument(options.After))
+func BanChannel(guildID string, ban *Ban) error {
+	return addBan(guildID, "banned", ban)
+}
 
-	guild := &Guild{}
-	err := res.Decode(guild)
-	if err != nil {
-		return err
-	}
+func UnbanChannel(guildID, channelID string) error {
+	return removeBan(guildID, "banned", channelID)
+}
 
-	GuildCache[guildID] = guild
-	return nil
+func BanUser(guildID string, ban *Ban) error {
+	return addBan(guildID, "blacklisted_users", ban)
 }
 
-func UnbanChannel(guildID, channelID string) error {
-	col := DB.Collection("guilds")
+func UnbanUser(guildID, userID string) error {
+	return removeBan(guildID, "blacklisted_users", userID)
+}
 
-	res := col.FindOneAndUpdate(context.Background(), bson.M{
-		"guild_id": guildID,
-	}, bson.M{
-		"$set": bson.M{
-			"updated_at": time.Now(),
-		},
-		"$pull": bson.M{
-			"banned": channelID,
-		},
-	}, options.FindOneAndUpdate().SetReturnDocument(options.After))
+func StarBanUser(guildID string, ban *Ban) error {
+	return addBan(guildID, "star_banned", ban)
+}
 
-	guild := &Guild{}
-	err := res.Decode(guild)
-	if err != nil {
-		return err
-	}
+func StarUnbanUser(guildID, userID string) error {
+	return removeBan(guildID, "star_banned", userID)
+}
 
-	GuildCache[guildID] = guild
-	return nil
+func SetStarRequirement(guildID, channelID string, stars int) error {
+	return setChannelSetting(guildID, channelID, "star_requirement", stars, &ChannelSettings{ID: channelID, StarRequirement: stars})
 }
 
-func BanUser(guildID, userID string) error {
-	col := DB.Collection("guilds")
+// SetChannelAge sets channel's minimum or maximum message age, field is either "min_age" or "max_age".
+// Nil age resets it to guild's default.
+func SetChannelAge(guildID, channelID, field string, age *time.Duration) error {
+	if age == nil {
+		return setChannelSetting(guildID, channelID, field, age, nil)
+	}
 
-	res := col.FindOneAndUpdate(context.Background(), bson.M{
-		"guild_id": guildID,
-	}, bson.M{
-		"$set": bson.M{
-			"updated_at": time.Now(),
-		},
-		"$addToSet": bson.M{
-			"blacklisted_users": userID,
-		},
-	}, options.FindOneAndUpdate().SetReturnDocument(options.After))
+	cs := &ChannelSettings{ID: channelID}
+	if field == "min_age" {
+		cs.MinAge = age
+	} else {
+		cs.MaxAge = age
+	}
 
-	guild := &Guild{}
-	err := res.Decode(guild)
-	if err != nil {
-		return err
+	return setChannelSetting(guildID, channelID, field, age, cs)
+}
+
+// SetChannelStarboard sets channel's starboard. Empty channel ID resets it to guild's default.
+func SetChannelStarboard(guildID, channelID, starboardID string) error {
+	if starboardID == "" {
+		return setChannelSetting(guildID, channelID, "starboard", starboardID, nil)
 	}
 
-	GuildCache[guildID] = guild
-	return nil
+	return setChannelSetting(guildID, channelID, "starboard", starboardID, &ChannelSettings{ID: channelID, Starboard: starboardID})
 }
 
-func UnbanUser(guildID, userID string) error {
-	col := DB.Collection("guilds")
+// SetChannelColour sets channel's embed colour. Zero resets it to guild's default.
+func SetChannelColour(guildID, channelID string, colour int64) error {
+	if colour == 0 {
+		return setChannelSetting(guildID, channelID, "color", colour, nil)
+	}
 
-	res := col.FindOneAndUpdate(context.Background(), bson.M{
-		"guild_id": guildID,
-	}, bson.M{
-		"$set": bson.M{
-			"updated_at": time.Now(),
-		},
-		"$pull": bson.M{
-			"blacklisted_users": userID,
-		},
-	}, options.FindOneAndUpdate().SetReturnDocument(options.After))
+	return setChannelSetting(guildID, channelID, "color", colour, &ChannelSettings{ID: channelID, Colour: colour})
+}
 
-	guild := &Guild{}
-	err := res.Decode(guild)
-	if err != nil {
-		return err
+func SetChannelUnstar(guildID, channelID string, policy *UnstarPolicy) error {
+	if policy == nil {
+		return setChannelSetting(guildID, channelID, "unstar", policy, nil)
 	}
 
-	GuildCache[guildID] = guild
-	return nil
+	return setChannelSetting(guildID, channelID, "unstar", policy, &ChannelSettings{ID: channelID, Unstar: policy})
 }
 
-func SetStarRequirement(guildID, channelID string, stars int) error {
+// setChannelSetting updates a field of existing channel settings or adds new settings if channel doesn't have any.
+// Nil settings reset the field, so nothing is added for a channel without settings.
+func setChannelSetting(guildID, channelID, field string, value interface{}, cs *ChannelSettings) error {
 	col := DB.Collection("guilds")
 	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
 	defer cancel()
 
-	cs := &ChannelSettings{channelID, stars}
 	res := col.FindOneAndUpdate(ctx, bson.M{
 		"guild_id":            guildID,
 		"channel_settings.id": channelID,
 	}, bson.M{
 		"$set": bson.M{
-			"updated_at":                          time.Now(),
-			"channel_settings.$.star_requirement": stars,
+			"updated_at":                  time.Now(),
+			"channel_settings.$." + field: value,
 		},
 	}, options.FindOneAndUpdate().SetReturnDocument(options.After))
 
 	if err := res.Err(); err != nil {
 		if err == mongo.ErrNoDocuments {
+			if cs == nil {
+				return nil
+			}
+
 			res = col.FindOneAndUpdate(ctx, bson.M{
 				"guild_id": guildID,
 			}, bson.M{
@@ -346,30 +465,35 @@ func SetStarRequirement(guildID, channelID string, stars int) error {
 	if err != nil {
 		return err
 	}
-	GuildCache[guildID] = guild
+	GuildCache.Set(guildID, guild)
 	return nil
 }
 
 func UnsetStarRequirement(guildID, channelID string) error {
 	col := DB.Collection("guilds")
 
+	// Only the star requirement is removed, other channel settings are kept.
 	res := col.FindOneAndUpdate(context.Background(), bson.M{
-		"guild_id": guildID,
+		"guild_id":            guildID,
+		"channel_settings.id": channelID,
 	}, bson.M{
 		"$set": bson.M{
 			"updated_at": time.Now(),
 		},
-		"$pull": bson.M{
-			"channel_settings": bson.M{"id": channelID},
+		"$unset": bson.M{
+			"channel_settings.$.star_requirement": "",
 		},
 	}, options.FindOneAndUpdate().SetReturnDocument(options.After))
 
 	guild := &Guild{}
 	err := res.Decode(guild)
 	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil
+		}
 		return err
 	}
 
-	GuildCache[guildID] = guild
+	GuildCache.Set(guildID, guild)
 	return nil
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/jasonlvhit/gocron"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 var (
@@ -27,7 +28,51 @@ type Message struct {
 	GuildID   string       `bson:"guild_id" json:"guild_id"`
 	Original  *MessagePair `bson:"original" json:"original"`
 	Starboard *MessagePair `bson:"starboard" json:"starboard"`
+	WebhookID string       `bson:"webhook_id,omitempty" json:"webhook_id,omitempty"`
 	CreatedAt time.Time    `bson:"created_at" json:"created_at"`
+	// PingedTier is a threshold of the highest tier whose role was already pinged.
+	PingedTier int     `bson:"pinged_tier" json:"pinged_tier"`
+	Copies     []*Copy `bson:"copies,omitempty" json:"copies,omitempty"`
+	// Forced posts were posted by a moderator regardless of star count.
+	Forced bool `bson:"forced" json:"forced"`
+	// Frozen posts aren't updated when stars change.
+	Frozen bool `bson:"frozen" json:"frozen"`
+	// Trashed messages were removed by a moderator and can't be reposted.
+	Trashed bool `bson:"trashed" json:"trashed"`
+}
+
+// Copy returns a repost on a board or nil if message wasn't posted there.
+func (m *Message) Copy(board string) *Copy {
+	for _, c := range m.Copies {
+		if c.Board == board {
+			return c
+		}
+	}
+
+	return nil
+}
+
+// CopyByMessage returns a copy with given message ID or nil if it's not one of copies.
+func (m *Message) CopyByMessage(messageID string) *Copy {
+	for _, c := range m.Copies {
+		if c.Message.MessageID == messageID {
+			return c
+		}
+	}
+
+	return nil
+}
+
+const (
+	// BoardHallOfFame is a board posts are promoted to once they reach a second, higher threshold.
+	BoardHallOfFame = "hall_of_fame"
+)
+
+// Copy is an additional repost of an original message on another board.
+type Copy struct {
+	Board     string       `bson:"board" json:"board"`
+	Message   *MessagePair `bson:"message" json:"message"`
+	WebhookID string       `bson:"webhook_id,omitempty" json:"webhook_id,omitempty"`
 }
 
 type MessagePair struct {
@@ -91,6 +136,133 @@ func DeleteMessage(pair *MessagePair) error {
 	return nil
 }
 
+func SetPingedTier(original *MessagePair, threshold int) error {
+	collection := DB.Collection("messages")
+	_, err := collection.UpdateOne(context.Background(), bson.M{
+		"original.channel_id": original.ChannelID,
+		"original.message_id": original.MessageID,
+	}, bson.M{
+		"$set": bson.M{"pinged_tier": threshold},
+	})
+	if err != nil {
+		return err
+	}
+
+	if m, ok := messageCache[*original]; ok {
+		m.PingedTier = threshold
+		messageCache[*original] = m
+	}
+
+	return nil
+}
+
+func SetFrozen(original *MessagePair, frozen bool) error {
+	collection := DB.Collection("messages")
+	_, err := collection.UpdateOne(context.Background(), bson.M{
+		"original.channel_id": original.ChannelID,
+		"original.message_id": original.MessageID,
+	}, bson.M{
+		"$set": bson.M{"frozen": frozen},
+	})
+	if err != nil {
+		return err
+	}
+
+	if m, ok := messageCache[*original]; ok {
+		m.Frozen = frozen
+		messageCache[*original] = m
+	}
+
+	return nil
+}
+
+// TrashMessage marks a message as trashed, forgetting its reposts. Creates a new record if it wasn't starboarded.
+func TrashMessage(original *MessagePair, guildID string) error {
+	collection := DB.Collection("messages")
+	_, err := collection.UpdateOne(context.Background(), bson.M{
+		"original.channel_id": original.ChannelID,
+		"original.message_id": original.MessageID,
+	}, bson.M{
+		"$set": bson.M{
+			"trashed":   true,
+			"starboard": nil,
+			"copies":    bson.A{},
+		},
+		"$setOnInsert": bson.M{
+			"guild_id":   guildID,
+			"original":   original,
+			"created_at": time.Now(),
+		},
+	}, options.Update().SetUpsert(true))
+	if err != nil {
+		return err
+	}
+
+	delete(messageCache, *original)
+	return nil
+}
+
+func AddCopy(original *MessagePair, c *Copy) error {
+	collection := DB.Collection("messages")
+	_, err := collection.UpdateOne(context.Background(), bson.M{
+		"original.channel_id": original.ChannelID,
+		"original.message_id": original.MessageID,
+	}, bson.M{
+		"$pull": bson.M{"copies": bson.M{"board": c.Board}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = collection.UpdateOne(context.Background(), bson.M{
+		"original.channel_id": original.ChannelID,
+		"original.message_id": original.MessageID,
+	}, bson.M{
+		"$push": bson.M{"copies": c},
+	})
+	if err != nil {
+		return err
+	}
+
+	if m, ok := messageCache[*original]; ok {
+		m.Copies = append(removeCopy(m.Copies, c.Board), c)
+		messageCache[*original] = m
+	}
+
+	return nil
+}
+
+func RemoveCopy(original *MessagePair, board string) error {
+	collection := DB.Collection("messages")
+	_, err := collection.UpdateOne(context.Background(), bson.M{
+		"original.channel_id": original.ChannelID,
+		"original.message_id": original.MessageID,
+	}, bson.M{
+		"$pull": bson.M{"copies": bson.M{"board": board}},
+	})
+	if err != nil {
+		return err
+	}
+
+	if m, ok := messageCache[*original]; ok {
+		m.Copies = removeCopy(m.Copies, board)
+		messageCache[*original] = m
+	}
+
+	return nil
+}
+
+func removeCopy(copies []*Copy, board string) []*Copy {
+	filtered := make([]*Copy, 0, len(copies))
+	for _, c := range copies {
+		if c.Board != board {
+			filtered = append(filtered, c)
+		}
+	}
+
+	return filtered
+}
+
 func Repost(channelID, id string) (*Message, error) {
 	m, ok := messageCache[NewPair(channelID, id)]
 
@@ -114,7 +286,12 @@ func RepostByStarboard(channelID, id string) (*Message, error) {
 
 	if !ok {
 		collection := DB.Collection("messages")
-		res := collection.FindOne(context.Background(), bson.D{{"starboard.channel_id", channelID}, {"starboard.message_id", id}})
+		res := collection.FindOne(context.Background(), bson.M{
+			"$or": bson.A{
+				bson.M{"starboard.channel_id": channelID, "starboard.message_id": id},
+				bson.M{"copies.message.channel_id": channelID, "copies.message.message_id": id},
+			},
+		})
 		if err := res.Err(); err != nil {
 			if err == mongo.ErrNoDocuments {
 				return nil, nil
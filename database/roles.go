@@ -0,0 +1,122 @@
+package database
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// StarRoles restrict who can star messages and how much their stars weigh.
+type StarRoles struct {
+	// Allow lists roles required to star messages. Empty list allows everyone.
+	Allow   []string      `json:"allow" bson:"allow"`
+	Deny    []string      `json:"deny" bson:"deny"`
+	Weights []*RoleWeight `json:"weights" bson:"weights"`
+}
+
+// RoleWeight makes stars of role's members count multiple times.
+type RoleWeight struct {
+	RoleID string `json:"role_id" bson:"role_id"`
+	Weight int    `json:"weight" bson:"weight"`
+}
+
+// HasStarRoles reports whether star count depends on starrers' roles.
+func (g *Guild) HasStarRoles() bool {
+	r := g.StarRoles
+	return r != nil && len(r.Allow)+len(r.Deny)+len(r.Weights) > 0
+}
+
+// MaxVoteWeight returns the most stars a single member can be worth.
+func (g *Guild) MaxVoteWeight() int {
+	weight := 1
+	if g.StarRoles == nil {
+		return weight
+	}
+
+	for _, w := range g.StarRoles.Weights {
+		if w.Weight > weight {
+			weight = w.Weight
+		}
+	}
+
+	return weight
+}
+
+// VoteWeight returns how many stars a member with given roles is worth.
+// Denied members and members without allowed roles are worth nothing, otherwise the highest role weight is used.
+func (g *Guild) VoteWeight(roles []string) int {
+	r := g.StarRoles
+	if r == nil {
+		return 1
+	}
+
+	for _, role := range roles {
+		if slices.Contains(r.Deny, role) {
+			return 0
+		}
+	}
+
+	if len(r.Allow) != 0 && !slices.ContainsFunc(roles, func(role string) bool { return slices.Contains(r.Allow, role) }) {
+		return 0
+	}
+
+	weight := 1
+	for _, w := range r.Weights {
+		if w.Weight > weight && slices.Contains(roles, w.RoleID) {
+			weight = w.Weight
+		}
+	}
+
+	return weight
+}
+
+// Copy returns a deep copy of star roles, so they can be modified without touching the cache. Nil is copied as empty rules.
+func (r *StarRoles) Copy() *StarRoles {
+	c := &StarRoles{
+		Allow:   make([]string, 0),
+		Deny:    make([]string, 0),
+		Weights: make([]*RoleWeight, 0),
+	}
+
+	if r == nil {
+		return c
+	}
+
+	c.Allow = append(c.Allow, r.Allow...)
+	c.Deny = append(c.Deny, r.Deny...)
+	for _, w := range r.Weights {
+		weight := *w
+		c.Weights = append(c.Weights, &weight)
+	}
+
+	return c
+}
+
+func (g *Guild) StarRolesToString() string {
+	if !g.HasStarRoles() {
+		return "everyone, 1 star each"
+	}
+
+	mentions := func(roles []string) string {
+		if len(roles) == 0 {
+			return "none"
+		}
+
+		formatted := make([]string, 0, len(roles))
+		for _, role := range roles {
+			formatted = append(formatted, fmt.Sprintf("<@&%v>", role))
+		}
+
+		return strings.Join(formatted, " ")
+	}
+
+	weights := make([]string, 0, len(g.StarRoles.Weights))
+	for _, w := range g.StarRoles.Weights {
+		weights = append(weights, fmt.Sprintf("<@&%v> ×%v", w.RoleID, w.Weight))
+	}
+	if len(weights) == 0 {
+		weights = append(weights, "none")
+	}
+
+	return fmt.Sprintf("**Allowed:** %v\n**Denied:** %v\n**Weights:** %v", mentions(g.StarRoles.Allow), mentions(g.StarRoles.Deny), strings.Join(weights, " | "))
+}
@@ -0,0 +1,113 @@
+package database
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	// TemplatePlaceholders lists placeholders recognised in starboard templates.
+	TemplatePlaceholders = []string{"{author}", "{channel}", "{count}", "{jump}", "{timestamp}", "{content}"}
+
+	placeholderRegex = regexp.MustCompile(`\{[^{}\s]*\}`)
+)
+
+// EmbedTemplate defines a layout of starboard posts. Empty strings hide a part of the embed.
+type EmbedTemplate struct {
+	Title         string           `json:"title" bson:"title"`
+	Author        string           `json:"author" bson:"author"`
+	Footer        string           `json:"footer" bson:"footer"`
+	Fields        []*TemplateField `json:"fields" bson:"fields"`
+	HideReply     bool             `json:"hide_reply" bson:"hide_reply"`
+	HideForwarded bool             `json:"hide_forwarded" bson:"hide_forwarded"`
+}
+
+type TemplateField struct {
+	Name   string `json:"name" bson:"name"`
+	Value  string `json:"value" bson:"value"`
+	Inline bool   `json:"inline" bson:"inline"`
+}
+
+// DefaultTemplate returns a template that matches the classic Eugen layout.
+func DefaultTemplate() *EmbedTemplate {
+	return &EmbedTemplate{
+		Author: "@{author} in #{channel}",
+		Footer: "{count}",
+		Fields: []*TemplateField{
+			{Name: "Original message", Value: "[Click here]({jump})", Inline: true},
+		},
+	}
+}
+
+// StarboardTemplate returns guild's embed template or the default one if it's not customised.
+func (g *Guild) StarboardTemplate() *EmbedTemplate {
+	if g.Template == nil {
+		return DefaultTemplate()
+	}
+
+	return g.Template
+}
+
+type templatePart struct {
+	name  string
+	text  string
+	limit int
+	// count reports whether {count} placeholder is allowed. Only title and footer are rewritten when star count changes.
+	count bool
+}
+
+// Validate checks placeholders and Discord embed limits.
+func (t *EmbedTemplate) Validate() error {
+	if len(t.Fields) > 10 {
+		return fmt.Errorf("too many fields, maximum is 10")
+	}
+
+	parts := []templatePart{
+		{"title", t.Title, 256, true},
+		{"author", t.Author, 256, false},
+		{"footer", t.Footer, 2048, true},
+	}
+
+	for i, f := range t.Fields {
+		if f.Name == "" || f.Value == "" {
+			return fmt.Errorf("field %v must have both name and value", i+1)
+		}
+
+		parts = append(parts,
+			templatePart{fmt.Sprintf("field %v name", i+1), f.Name, 256, false},
+			templatePart{fmt.Sprintf("field %v value", i+1), f.Value, 1024, false},
+		)
+	}
+
+	for _, part := range parts {
+		if len(part.text) > part.limit {
+			return fmt.Errorf("%v is too long, maximum is %v characters", part.name, part.limit)
+		}
+
+		for _, placeholder := range placeholderRegex.FindAllString(part.text, -1) {
+			if !slices.Contains(TemplatePlaceholders, placeholder) {
+				return fmt.Errorf("unknown placeholder %v in %v. Available placeholders: %v", placeholder, part.name, strings.Join(TemplatePlaceholders, ", "))
+			}
+
+			if placeholder == "{count}" && !part.count {
+				return fmt.Errorf("{count} placeholder is only supported in title and footer")
+			}
+		}
+	}
+
+	return nil
+}
+
+// Copy returns a deep copy of a template, so it can be modified without touching the cache.
+func (t *EmbedTemplate) Copy() *EmbedTemplate {
+	c := *t
+	c.Fields = make([]*TemplateField, 0, len(t.Fields))
+	for _, f := range t.Fields {
+		field := *f
+		c.Fields = append(c.Fields, &field)
+	}
+
+	return &c
+}
@@ -0,0 +1,73 @@
+package database
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// StarTier changes starboard post appearance once it reaches a number of stars.
+type StarTier struct {
+	Threshold int    `json:"threshold" bson:"threshold"`
+	Emoji     string `json:"emoji" bson:"emoji"`
+	// Colour overrides guild's embed colour, 0 keeps the default one.
+	Colour int64  `json:"color" bson:"color"`
+	RoleID string `json:"role_id,omitempty" bson:"role_id,omitempty"`
+}
+
+// Tier returns the highest tier reached by a star count or nil if none were reached.
+func (g *Guild) Tier(count int) *StarTier {
+	var tier *StarTier
+	for _, t := range g.Tiers {
+		if count >= t.Threshold && (tier == nil || t.Threshold > tier.Threshold) {
+			tier = t
+		}
+	}
+
+	return tier
+}
+
+// Colour returns embed colour of a post from a channel with given star count.
+// Tier colours take precedence over channel colours.
+func (g *Guild) Colour(channelID string, count int) int {
+	if tier := g.Tier(count); tier != nil && tier.Colour != 0 {
+		return int(tier.Colour)
+	}
+
+	if cs := g.channelSettings(channelID, func(cs *ChannelSettings) bool { return cs.Colour != 0 }); cs != nil {
+		return int(cs.Colour)
+	}
+
+	return int(g.EmbedColour)
+}
+
+// SortTiers sorts tiers by threshold in ascending order.
+func SortTiers(tiers []*StarTier) {
+	sort.Slice(tiers, func(i, j int) bool {
+		return tiers[i].Threshold < tiers[j].Threshold
+	})
+}
+
+func (g *Guild) TiersToString() string {
+	if len(g.Tiers) == 0 {
+		return "none"
+	}
+
+	var sb strings.Builder
+	for i, t := range g.Tiers {
+		if i != 0 {
+			sb.WriteString(" | ")
+		}
+
+		sb.WriteString(fmt.Sprintf("%v **%v**", t.Emoji, t.Threshold))
+		if t.Colour != 0 {
+			sb.WriteString(fmt.Sprintf(" ``#%06x``", t.Colour))
+		}
+
+		if t.RoleID != "" {
+			sb.WriteString(fmt.Sprintf(" <@&%v>", t.RoleID))
+		}
+	}
+
+	return sb.String()
+}
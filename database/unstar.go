@@ -0,0 +1,89 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+const (
+	// UnstarHalf removes a repost once it loses half of required stars. It's the default mode.
+	UnstarHalf = "half"
+	// UnstarThreshold removes a repost once its stars drop to a custom threshold.
+	UnstarThreshold = "threshold"
+	// UnstarNever keeps updating a repost's star count, but never removes it.
+	UnstarNever = "never"
+	// UnstarFreeze keeps a repost and its highest star count once posted.
+	UnstarFreeze = "freeze"
+)
+
+// UnstarPolicy defines what happens to a repost when stars are removed from the original message.
+type UnstarPolicy struct {
+	Mode      string `json:"mode" bson:"mode"`
+	Threshold int    `json:"threshold" bson:"threshold"`
+}
+
+// ParseUnstarPolicy parses half, never, freeze or a number of stars to remove a repost at.
+func ParseUnstarPolicy(str string) (*UnstarPolicy, error) {
+	switch str {
+	case UnstarHalf, UnstarNever, UnstarFreeze:
+		return &UnstarPolicy{Mode: str}, nil
+	}
+
+	threshold, err := strconv.Atoi(str)
+	if err != nil {
+		return nil, errors.New("unstar mode should be one of ``half``, ``never``, ``freeze`` or a number of stars")
+	}
+
+	if threshold < 0 {
+		return nil, fmt.Errorf("unstar threshold should be >= 0, provided threshold is %v", threshold)
+	}
+
+	return &UnstarPolicy{Mode: UnstarThreshold, Threshold: threshold}, nil
+}
+
+// ShouldRemove reports whether a repost with given star count should be removed.
+func (p *UnstarPolicy) ShouldRemove(count, required int) bool {
+	switch p.Mode {
+	case UnstarNever, UnstarFreeze:
+		return false
+	case UnstarThreshold:
+		return count <= p.Threshold
+	default:
+		return count <= required/2
+	}
+}
+
+// Frozen reports whether star count of a repost shouldn't go down.
+func (p *UnstarPolicy) Frozen() bool {
+	return p.Mode == UnstarFreeze
+}
+
+// Keeps reports whether a repost is never removed because of stars.
+func (p *UnstarPolicy) Keeps() bool {
+	return p.Mode == UnstarNever || p.Mode == UnstarFreeze
+}
+
+func (p *UnstarPolicy) String() string {
+	switch p.Mode {
+	case UnstarThreshold:
+		return fmt.Sprintf("at %v", p.Threshold)
+	case "":
+		return UnstarHalf
+	default:
+		return p.Mode
+	}
+}
+
+// UnstarPolicy returns channel's unstar policy or guild's one if channel doesn't override it.
+func (g *Guild) UnstarPolicy(channelID string) *UnstarPolicy {
+	if cs := g.channelSettings(channelID, func(cs *ChannelSettings) bool { return cs.Unstar != nil }); cs != nil {
+		return cs.Unstar
+	}
+
+	if g.Unstar == nil {
+		return &UnstarPolicy{Mode: UnstarHalf}
+	}
+
+	return g.Unstar
+}
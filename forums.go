@@ -0,0 +1,84 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// maxForumPostName is the longest name of a forum post.
+	maxForumPostName = 100
+	// maxForumPostTags is the largest number of tags applied to a forum post.
+	maxForumPostTags = 5
+)
+
+// sendForumRepost starts a forum post with a repost as its first message. Post name is the original author and channel,
+// post is tagged with forum's tags named after the original channel or its parents.
+func sendForumRepost(s *discordgo.Session, forum *discordgo.Channel, message *discordgo.Message, msg *discordgo.MessageSend) (*discordgo.Message, error) {
+	source, err := stateChannel(s, message.ChannelID)
+	if err != nil {
+		return nil, err
+	}
+
+	name := "#" + channelLabel(s, source)
+	if isVoiceChannel(source) {
+		name = "🔊 " + source.Name
+	}
+	if message.Author != nil {
+		name = displayName(message.Author) + " in " + name
+	}
+
+	if runes := []rune(name); len(runes) > maxForumPostName {
+		name = string(runes[:maxForumPostName-1]) + "…"
+	}
+
+	thread, err := s.ForumThreadStartComplex(forum.ID, &discordgo.ThreadStart{
+		Name:        name,
+		AppliedTags: forumTags(s, forum, source),
+	}, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	// The first message of a forum post shares its ID.
+	sent, err := s.ChannelMessage(thread.ID, thread.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return sent, nil
+}
+
+// forumTags returns IDs of forum's tags whose names match a channel or one of its parents.
+func forumTags(s *discordgo.Session, forum, ch *discordgo.Channel) []string {
+	names := map[string]bool{strings.ToLower(ch.Name): true}
+	for parentID, depth := ch.ParentID, 1; parentID != "" && depth < 3; depth++ {
+		parent, err := stateChannel(s, parentID)
+		if err != nil {
+			break
+		}
+
+		names[strings.ToLower(parent.Name)] = true
+		parentID = parent.ParentID
+	}
+
+	tags := make([]string, 0)
+	for _, tag := range forum.AvailableTags {
+		if names[strings.ToLower(tag.Name)] && len(tags) < maxForumPostTags {
+			tags = append(tags, tag.ID)
+		}
+	}
+
+	return tags
+}
+
+// deleteRepost deletes a starboard message. Forum posts that start with it are deleted together with it.
+func deleteRepost(s *discordgo.Session, channelID, messageID string) error {
+	if channelID == messageID {
+		_, err := s.ChannelDelete(channelID)
+		return err
+	}
+
+	return s.ChannelMessageDelete(channelID, messageID)
+}
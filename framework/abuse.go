@@ -0,0 +1,125 @@
+package framework
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/VTGare/Eugen/database"
+	"github.com/VTGare/Eugen/utils"
+	"github.com/bwmarrin/discordgo"
+)
+
+func antiAbuse(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	guild := database.GuildCache.Get(m.GuildID)
+
+	if len(args) == 0 {
+		embed := utils.BaseEmbed(s)
+		embed.Title = "Anti-abuse"
+		embed.Description = antiAbuseToString(guild)
+		s.ChannelMessageSendEmbed(m.ChannelID, embed)
+		return nil
+	}
+
+	isAdmin, err := utils.MemberHasPermission(s, m.GuildID, m.Author.ID, discordgo.PermissionAdministrator)
+	if err != nil {
+		return err
+	}
+	if !isAdmin {
+		return utils.ErrNoPermission
+	}
+
+	if args[0] == "off" {
+		if err := changeSetting(s, m, "antiabuse", nil); err != nil {
+			return err
+		}
+
+		s.ChannelMessageSend(m.ChannelID, "Successfully disabled anti-abuse checks.")
+		return nil
+	}
+
+	if len(args) < 2 {
+		return utils.ErrNotEnoughArguments
+	}
+
+	updated := guild.AntiAbuse.Copy()
+	switch args[0] {
+	case "accountage":
+		updated.MinAccountAge, err = parseOptionalDuration(args[1])
+	case "memberage":
+		updated.MinMemberAge, err = parseOptionalDuration(args[1])
+	case "dailycap":
+		if args[1] == "none" {
+			updated.DailyAuthorCap = 0
+			break
+		}
+
+		updated.DailyAuthorCap, err = strconv.Atoi(args[1])
+		if err != nil || updated.DailyAuthorCap < 1 {
+			return utils.ErrParsingArgument
+		}
+	case "burst":
+		if args[1] == "none" {
+			updated.BurstSize, updated.BurstWindow = 0, 0
+			break
+		}
+
+		if len(args) < 3 {
+			return utils.ErrNotEnoughArguments
+		}
+
+		updated.BurstSize, err = strconv.Atoi(args[1])
+		if err != nil || updated.BurstSize < 2 {
+			return fmt.Errorf("burst size should be a number greater than 1")
+		}
+
+		updated.BurstWindow, err = utils.ParseDuration(args[2])
+	default:
+		return errors.New("incorrect command usage. Please use e!help antiabuse command for more information")
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if err := changeSetting(s, m, "antiabuse", updated); err != nil {
+		return err
+	}
+
+	s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Successfully updated anti-abuse checks:\n%v", antiAbuseToString(database.GuildCache.Get(m.GuildID))))
+	return nil
+}
+
+// parseOptionalDuration parses a duration or “none“ as zero.
+func parseOptionalDuration(str string) (time.Duration, error) {
+	if str == "none" {
+		return 0, nil
+	}
+
+	return utils.ParseDuration(str)
+}
+
+func antiAbuseToString(guild *database.Guild) string {
+	if !guild.HasAntiAbuse() {
+		return "disabled"
+	}
+
+	var (
+		a        = guild.AntiAbuse
+		dailyCap = "none"
+		burst    = "none"
+	)
+
+	if a.DailyAuthorCap > 0 {
+		dailyCap = fmt.Sprintf("%v posts", a.DailyAuthorCap)
+	}
+
+	if a.BurstSize > 0 && a.BurstWindow > 0 {
+		burst = fmt.Sprintf("%v stars in %v", a.BurstSize, utils.FormatDuration(a.BurstWindow))
+	}
+
+	return fmt.Sprintf("**Account age:** %v | **Member age:** %v | **Daily cap per author:** %v | **Burst:** %v",
+		utils.FormatDuration(a.MinAccountAge), utils.FormatDuration(a.MinMemberAge), dailyCap, burst,
+	)
+}
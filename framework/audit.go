@@ -0,0 +1,127 @@
+package framework
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/VTGare/Eugen/database"
+	"github.com/VTGare/Eugen/utils"
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const historyPageSize = 10
+
+func history(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	ok, err := utils.MemberHasPermission(s, m.GuildID, m.Author.ID, discordgo.PermissionAdministrator|discordgo.PermissionManageServer)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return fmt.Errorf("You don't have enough permissions to run this command.")
+	}
+
+	page := 1
+	if len(args) != 0 {
+		page, err = strconv.Atoi(args[0])
+		if err != nil || page < 1 {
+			return utils.ErrParsingArgument
+		}
+	}
+
+	entries, err := database.AuditHistory(m.GuildID, int64((page-1)*historyPageSize), historyPageSize)
+	if err != nil {
+		return err
+	}
+
+	embed := utils.BaseEmbed(s)
+	embed.Title = fmt.Sprintf("Settings history | Page %v", page)
+	if len(entries) == 0 {
+		embed.Description = "No changes were recorded."
+	} else {
+		embed.Description = "Use ``e!rollback <id>`` to revert a change."
+	}
+
+	for _, entry := range entries {
+		actor := "automatically"
+		if entry.ActorID != "" {
+			actor = fmt.Sprintf("by <@%v>", entry.ActorID)
+		}
+
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name: fmt.Sprintf("%v | %v", entry.Field, entry.ID.Hex()),
+			Value: fmt.Sprintf("``%v`` → ``%v``\n%v <t:%v:R>",
+				auditValueToString(entry.Field, entry.OldValue), auditValueToString(entry.Field, entry.NewValue), actor, entry.CreatedAt.Unix(),
+			),
+		})
+	}
+
+	s.ChannelMessageSendEmbed(m.ChannelID, embed)
+	return nil
+}
+
+func rollback(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	isAdmin, err := utils.MemberHasPermission(s, m.GuildID, m.Author.ID, discordgo.PermissionAdministrator)
+	if err != nil {
+		return err
+	}
+	if !isAdmin {
+		return utils.ErrNoPermission
+	}
+
+	if len(args) == 0 {
+		return utils.ErrNotEnoughArguments
+	}
+
+	entry, err := database.AuditByID(m.GuildID, args[0])
+	if err != nil {
+		return err
+	}
+
+	if err := database.Rollback(entry, m.Author.ID); err != nil {
+		if errors.Is(err, database.ErrAuditOutdated) {
+			return fmt.Errorf("``%v`` %v", entry.Field, err)
+		}
+		return err
+	}
+
+	utils.LogAction(s, &utils.LogEntry{
+		Action:  utils.ActionSettings,
+		GuildID: m.GuildID,
+		UserID:  m.Author.ID,
+		Details: fmt.Sprintf("``%v`` rolled back to ``%v``", entry.Field, auditValueToString(entry.Field, entry.OldValue)),
+	})
+
+	s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Successfully rolled back ``%v`` to ``%v``", entry.Field, auditValueToString(entry.Field, entry.OldValue)))
+	return nil
+}
+
+func auditValueToString(field string, value bson.RawValue) string {
+	var str string
+	switch value.Type {
+	case bson.TypeNull, 0:
+		str = "none"
+	case bson.TypeInt64:
+		if field == "maxage" || field == "minage" {
+			str = utils.FormatDuration(time.Duration(value.Int64()))
+		} else {
+			str = strconv.FormatInt(value.Int64(), 10)
+		}
+	case bson.TypeString:
+		str = value.StringValue()
+		if str == "" {
+			str = "none"
+		}
+	default:
+		str = value.String()
+	}
+
+	if runes := []rune(str); len(runes) > 100 {
+		str = string(runes[:100]) + "…"
+	}
+
+	return str
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -47,7 +48,7 @@ func init() {
 			},
 			{
 				Name:  "starboard",
-				Value: "Starboard channel. Required for starboard to work. Accepts channel ID or channel mention.",
+				Value: "Starboard channel. Required for starboard to work. Accepts channel ID or channel mention. In a forum channel each repost becomes its own post, tagged with tags named after the original channel.",
 			},
 			{
 				Name:  "emote",
@@ -57,14 +58,140 @@ func init() {
 				Name:  "stars",
 				Value: "Stars required to repost a message to starboard channel.",
 			},
+			{
+				Name:  "maxage",
+				Value: "Messages older than this can't be starboarded, e.g. ``30d``. Accepts ``none`` to disable.",
+			},
+			{
+				Name:  "minage",
+				Value: "Messages younger than this can't be starboarded yet, e.g. ``10m``. Accepts ``none`` to disable.",
+			},
+			{
+				Name:  "unstar",
+				Value: "What happens to a repost when stars are removed. ``half`` removes it at half of required stars (default), a number removes it at that many stars, ``never`` keeps it and ``freeze`` keeps it with its highest star count.",
+			},
+			{
+				Name:  "halloffame",
+				Value: "Hall of fame channel. Starboard posts are cross-posted there once they reach ``halloffamestars`` and removed if they fall below it. Accepts channel ID, channel mention or ``none``.",
+			},
+			{
+				Name:  "halloffamestars",
+				Value: "Stars required to promote a post to hall of fame. Must be greater than ``stars``.",
+			},
+			{
+				Name:  "template",
+				Value: "Changes starboard embed layout. See ``{prefix}help template`` for more info.",
+			},
+			{
+				Name:  "webhook",
+				Value: "Posts reposts through a webhook with original author's name and avatar. Requires ***Manage Webhooks*** permission in the starboard channel.",
+			},
+			{
+				Name:  "plainmentions",
+				Value: "Shows mentions in reposted messages as plain names instead of clickable mentions. Reposts never ping anyone either way. Accepts ***true*** or ***false***.",
+			},
+			{
+				Name:  "removebannedstars",
+				Value: "Removes reactions of users banned from starring with ``starban``. Accepts ***true*** or ***false***.",
+			},
+			{
+				Name:  "logchannel",
+				Value: "Channel that logs starboard posts, edits, deletions, moderation actions, settings changes and errors. Accepts channel ID, channel mention or ``none``.",
+			},
 		},
-	}).setGuildOnly(true)
+	}).setGuildOnly(true).setRawArgs(true)
 
-	banCommand := newCommand("ban", "Bans a channel").setExec(ban).setGuildOnly(true)
+	templateCommand := newCommand("template", "Changes starboard embed layout.").setExec(template).setGuildOnly(true).setRawArgs(true).setHelp(&HelpSettings{
+		IsVisible:    true,
+		ExtendedHelp: templateHelp,
+	})
+	tiersCommand := newCommand("tiers", "Lists or changes star milestone tiers.").setExec(tiers).setGuildOnly(true).setRawArgs(true).setAliases("tier", "milestones")
+	tiersCommand.Help.ExtendedHelp = []*discordgo.MessageEmbedField{
+		{
+			Name:  "Usage",
+			Value: "{prefix}tiers ``add <stars> <emoji> [colour] [@role]``, ``remove <stars>`` or ``clear``",
+		},
+		{
+			Name:  "Stars",
+			Value: "Required. Posts with at least this many stars use tier's emoji and colour. Posts are downgraded when stars are removed.",
+		},
+		{
+			Name:  "Colour",
+			Value: "Optional. Hexadecimal embed colour, e.g. ``ffd700``.",
+		},
+		{
+			Name:  "Role",
+			Value: "Optional. Role mention to ping once a post reaches the tier.",
+		},
+	}
+	starRolesCommand := newCommand("starroles", "Lists or changes roles allowed to star messages and their vote weights.").setExec(starRoles).setGuildOnly(true).setAliases("roles")
+	starRolesCommand.Help.ExtendedHelp = []*discordgo.MessageEmbedField{
+		{
+			Name:  "Usage",
+			Value: "{prefix}starroles ``allow <@role>``, ``deny <@role>``, ``weight <@role> <weight>``, ``remove <@role>`` or ``clear``",
+		},
+		{
+			Name:  "allow / deny",
+			Value: "If any roles are allowed, only their members can star messages. Stars of denied roles' members are never counted.",
+		},
+		{
+			Name:  "weight",
+			Value: "Stars of role's members count multiple times, from 1 to 10. Members with several roles use the highest weight.",
+		},
+	}
+	antiAbuseCommand := newCommand("antiabuse", "Lists or changes checks that discount suspicious stars.").setExec(antiAbuse).setGuildOnly(true).setAliases("abuse")
+	antiAbuseCommand.Help.ExtendedHelp = []*discordgo.MessageEmbedField{
+		{
+			Name:  "Usage",
+			Value: "{prefix}antiabuse ``accountage <duration>``, ``memberage <duration>``, ``dailycap <posts>``, ``burst <stars> <duration>`` or ``off``. Use ``none`` to disable a single check.",
+		},
+		{
+			Name:  "accountage / memberage",
+			Value: "Stars from accounts younger than duration or members who joined the server more recently aren't counted.",
+		},
+		{
+			Name:  "dailycap",
+			Value: "How many posts of one author a user can star per day. Further stars aren't counted.",
+		},
+		{
+			Name:  "burst",
+			Value: "If at least this many members who joined in the last week star a message within duration, their stars aren't counted.",
+		},
+		{
+			Name:  "Log channel",
+			Value: "Posts with discounted stars are reported to ``logchannel`` for review.",
+		},
+	}
+	filtersCommand := newCommand("filters", "Lists or changes filters that prevent messages from being starboarded.").setExec(filters).setGuildOnly(true).setRawArgs(true).setAliases("filter")
+	filtersCommand.Help.ExtendedHelp = []*discordgo.MessageEmbedField{
+		{
+			Name:  "Usage",
+			Value: "{prefix}filters ``add <kind> [pattern]``, ``remove <number>``, ``clear`` or ``report <true|false>``",
+		},
+		{
+			Name:  "Kinds",
+			Value: "``substring <text>`` and ``word <word>`` are case-insensitive, ``regex <expression>``, ``invite`` matches server invites, ``mentions [limit]`` matches @everyone and messages with at least limit (5 by default) mentions.",
+		},
+		{
+			Name:  "report",
+			Value: "Reports filtered messages that reached star requirement to ``logchannel``.",
+		},
+	}
+	forceCommand := newCommand("force", "Posts a message to the starboard regardless of its stars. Usage: ``{prefix}force <message link>``").setExec(force).setGuildOnly(true)
+	trashCommand := newCommand("trash", "Removes a message from the starboard and prevents it from being reposted. Usage: ``{prefix}trash <message link>``").setExec(trash).setGuildOnly(true)
+	freezeCommand := newCommand("freeze", "Locks star count of a starboard post. Usage: ``{prefix}freeze <message link>``").setExec(freeze).setGuildOnly(true)
+	unfreezeCommand := newCommand("unfreeze", "Unlocks star count of a frozen starboard post. Usage: ``{prefix}unfreeze <message link>``").setExec(unfreeze).setGuildOnly(true)
+	historyCommand := newCommand("history", "Shows recent changes of server's settings. Usage: ``{prefix}history [page]``").setExec(history).setGuildOnly(true)
+	rollbackCommand := newCommand("rollback", "Reverts a settings change. Usage: ``{prefix}rollback <id from history>``").setExec(rollback).setGuildOnly(true)
+	previewCommand := newCommand("preview", "Renders a sample starboard post using current template.").setExec(preview).setGuildOnly(true)
+
+	banCommand := newCommand("ban", "Bans channels, categories or threads, including threads and channels inside them. Usage: ``{prefix}ban <channels...> [duration] [reason]``, e.g. ``{prefix}ban #memes 7d off-topic``").setExec(ban).setGuildOnly(true).setRawArgs(true)
 	unbanCommand := newCommand("unban", "Unbans a channel").setExec(unban).setGuildOnly(true)
 
-	blacklistCommand := newCommand("blacklist", "Blacklists a user").setExec(blacklist).setGuildOnly(true)
+	blacklistCommand := newCommand("blacklist", "Blacklists users. Usage: ``{prefix}blacklist <users...> [duration] [reason]``, e.g. ``{prefix}blacklist @user 7d spamming``").setExec(blacklist).setGuildOnly(true).setRawArgs(true)
 	unblacklistCommand := newCommand("unblacklist", "Unblacklists a user").setExec(unblacklist).setGuildOnly(true)
+	starBanCommand := newCommand("starban", "Prevents users from starring messages. Their messages can still be starboarded, use ``blacklist`` for that. Usage: ``{prefix}starban <users...> [duration] [reason]``").setExec(starBan).setGuildOnly(true).setRawArgs(true)
+	starUnbanCommand := newCommand("starunban", "Allows star-banned users to star messages again.").setExec(starUnban).setGuildOnly(true)
 
 	reqCommand := newCommand("req", "Sets per channel star requirement").setExec(req).setGuildOnly(true).setAliases("requirement", "channelstars", "channelset")
 	reqCommand.Help.ExtendedHelp = []*discordgo.MessageEmbedField{
@@ -74,12 +201,28 @@ func init() {
 		},
 		{
 			Name:  "Channel ID or mention",
-			Value: "Required. It must be a channel on this server!",
+			Value: "Required. It must be a channel on this server! Settings of a category apply to its channels and settings of a channel or a forum apply to its threads and posts, unless they have their own.",
 		},
 		{
 			Name:  "Star requirement",
 			Value: "Required. It must be an integer greater than or equal to 1 or ``default`` to remove a custom star requirement.",
 		},
+		{
+			Name:  "Message age",
+			Value: "e!req <channel id or mention> <maxage or minage> <duration>. Accepts durations like ``12h`` or ``7d``, ``none`` to disable the limit in this channel and ``default`` to use server's limit.",
+		},
+		{
+			Name:  "Unstar behaviour",
+			Value: "e!req <channel id or mention> unstar <mode>. See ``unstar`` setting in ``{prefix}help set`` for modes, ``default`` uses server's behaviour.",
+		},
+		{
+			Name:  "Starboard",
+			Value: "e!req <channel id or mention> starboard <channel id or mention>. Reposts messages from this channel to another starboard, which can be a forum channel. ``default`` uses server's starboard.",
+		},
+		{
+			Name:  "Color",
+			Value: "e!req <channel id or mention> color <color>. Changes embed color of reposts from this channel, star tier colors still take precedence. ``default`` uses server's color.",
+		},
 	}
 
 	inviteCmd := newCommand("invite", "Sends an invite link").setExec(invite)
@@ -94,6 +237,20 @@ func init() {
 	basicGroup.addCommand(setupCommand)
 	basicGroup.addCommand(blacklistCommand)
 	basicGroup.addCommand(unblacklistCommand)
+	basicGroup.addCommand(starBanCommand)
+	basicGroup.addCommand(starUnbanCommand)
+	basicGroup.addCommand(templateCommand)
+	basicGroup.addCommand(previewCommand)
+	basicGroup.addCommand(tiersCommand)
+	basicGroup.addCommand(starRolesCommand)
+	basicGroup.addCommand(antiAbuseCommand)
+	basicGroup.addCommand(filtersCommand)
+	basicGroup.addCommand(forceCommand)
+	basicGroup.addCommand(trashCommand)
+	basicGroup.addCommand(freezeCommand)
+	basicGroup.addCommand(unfreezeCommand)
+	basicGroup.addCommand(historyCommand)
+	basicGroup.addCommand(rollbackCommand)
 	CommandGroups["basic"] = basicGroup
 }
 
@@ -110,7 +267,7 @@ func ping(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error
 }
 
 func help(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
-	guild := database.GuildCache[m.GuildID]
+	guild := database.GuildCache.Get(m.GuildID)
 
 	embed := &discordgo.MessageEmbed{
 		Description: fmt.Sprintf("Use ``%vhelp <command name>`` for extended help on specific commands.", guild.Prefix),
@@ -164,7 +321,74 @@ func help(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error
 	return nil
 }
 
+// banList describes a list of banned users or channels managed by ban commands.
+type banList struct {
+	// resolve returns an ID of a target that can be banned or an empty string if it should be skipped.
+	resolve  func(s *discordgo.Session, m *discordgo.MessageCreate, arg string) (string, error)
+	isBanned func(g *database.Guild, id string) bool
+	ban      func(guildID string, ban *database.Ban) error
+	unban    func(guildID, id string) error
+	// noun, trim and mention name targets, and are a cutset and a format of their mentions.
+	noun    string
+	trim    string
+	mention string
+	// bannedLog and bannedTitle describe added bans, unbannedLog and unbannedTitle describe lifted ones.
+	bannedLog, bannedTitle     string
+	unbannedLog, unbannedTitle string
+}
+
+var (
+	// durationLikeRegex matches ban arguments meant as a duration, e.g. 7d, 1d12h or a mistyped 7days.
+	durationLikeRegex = regexp.MustCompile(`^(\d+[a-zA-Z]+)+$`)
+
+	channelBans = &banList{
+		resolve:  resolveChannel,
+		isBanned: func(g *database.Guild, id string) bool { return g.ChannelBan(id) != nil },
+		ban:      database.BanChannel,
+		unban:    database.UnbanChannel,
+		noun:     "channels",
+		trim:     "<#>",
+		mention:  "<#%v>",
+
+		bannedLog:     "Banned channels",
+		bannedTitle:   "banned channels",
+		unbannedLog:   "Unbanned channels",
+		unbannedTitle: "unbanned channels",
+	}
+	blacklistedUsers = &banList{
+		resolve:  resolveUser,
+		isBanned: (*database.Guild).IsBlacklisted,
+		ban:      database.BanUser,
+		unban:    database.UnbanUser,
+		noun:     "users",
+		trim:     "<@!>",
+		mention:  "<@%v>",
+
+		bannedLog:     "Blacklisted users",
+		bannedTitle:   "blacklisted users",
+		unbannedLog:   "Unblacklisted users",
+		unbannedTitle: "unblacklisted users",
+	}
+)
+
 func ban(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	return channelBans.add(s, m, args)
+}
+
+func unban(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	return channelBans.remove(s, m, args)
+}
+
+func blacklist(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	return blacklistedUsers.add(s, m, args)
+}
+
+func unblacklist(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	return blacklistedUsers.remove(s, m, args)
+}
+
+// add bans targets from command arguments followed by optional duration and reason.
+func (l *banList) add(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
 	ok, err := utils.MemberHasPermission(s, m.GuildID, m.Author.ID, discordgo.PermissionAdministrator|discordgo.PermissionManageServer)
 	if err != nil {
 		return err
@@ -178,45 +402,55 @@ func ban(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error
 		return utils.ErrNotEnoughArguments
 	}
 
-	guild := database.GuildCache[m.GuildID]
+	guild := database.GuildCache.Get(m.GuildID)
+	targets, expiresAt, reason, err := parseBanArgs(args)
+	if err != nil {
+		return err
+	}
 
 	banned := make([]string, 0)
-	for _, arg := range args {
-		if strings.HasPrefix(arg, "<#") {
-			arg = strings.Trim(arg, "<#>")
-		}
-
-		ch, err := s.Channel(arg)
+	for _, arg := range targets {
+		id, err := l.resolve(s, m, strings.Trim(arg, l.trim))
 		if err != nil {
-			switch {
-			case strings.Contains(err.Error(), "403"):
-				return fmt.Errorf("Unable to get channel: <#%v>. Not enough permissions.", arg)
-			default:
-				return err
-			}
+			return err
 		}
 
-		if ch.GuildID == m.GuildID {
-			if !guild.IsBanned(ch.ID) {
-				err := database.BanChannel(ch.GuildID, ch.ID)
-				if err != nil {
-					return err
-				}
+		if id == "" {
+			continue
+		}
 
-				banned = append(banned, fmt.Sprintf("<#%v>", ch.ID))
-			}
+		err = l.ban(guild.ID, &database.Ban{
+			ID:        id,
+			Reason:    reason,
+			ActorID:   m.Author.ID,
+			CreatedAt: time.Now(),
+			ExpiresAt: expiresAt,
+		})
+		if err != nil {
+			logrus.Warnf("banList.add(): %v", err)
+			continue
 		}
+
+		banned = append(banned, fmt.Sprintf(l.mention, id))
 	}
 
 	embed := utils.BaseEmbed(s)
-	embed.Title = "✅ Successfully banned channels"
-	embed.Description = fmt.Sprintf("List of banned channels:\n%v", banned)
+	if len(banned) > 0 {
+		logSettings(s, m, guild, fmt.Sprintf("%v: %v %v", l.bannedLog, strings.Join(banned, " "), (&database.Ban{ExpiresAt: expiresAt, Reason: reason}).String()))
+		embed.Title = "✅ Successfully " + l.bannedTitle
+		embed.Description = strings.Join(banned, " ")
+		embed.Fields = banDetails(expiresAt, reason)
+	} else {
+		embed.Title = "❎ Nothing changed"
+		embed.Description = fmt.Sprintf("None of the %v could be found.", l.noun)
+	}
 
 	s.ChannelMessageSendEmbed(m.ChannelID, embed)
 	return nil
 }
 
-func unban(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+// remove lifts bans of targets from command arguments.
+func (l *banList) remove(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
 	ok, err := utils.MemberHasPermission(s, m.GuildID, m.Author.ID, discordgo.PermissionAdministrator|discordgo.PermissionManageServer)
 	if err != nil {
 		return err
@@ -230,122 +464,130 @@ func unban(s *discordgo.Session, m *discordgo.MessageCreate, args []string) erro
 		return utils.ErrNotEnoughArguments
 	}
 
-	guild := database.GuildCache[m.GuildID]
+	guild := database.GuildCache.Get(m.GuildID)
 	unbanned := make([]string, 0)
 	for _, arg := range args {
-		arg = strings.Trim(arg, "<#>")
+		arg = strings.Trim(arg, l.trim)
 
-		exists := false
-		for _, ch := range guild.BannedChannels {
-			if arg == ch {
-				exists = true
-			}
-		}
-
-		if exists {
-			err = database.UnbanChannel(guild.ID, arg)
+		if l.isBanned(guild, arg) {
+			err = l.unban(guild.ID, arg)
 			if err != nil {
 				return err
 			}
 
-			unbanned = append(unbanned, fmt.Sprintf("<#%v>", arg))
+			unbanned = append(unbanned, fmt.Sprintf(l.mention, arg))
 		}
 	}
 
 	embed := utils.BaseEmbed(s)
 	if len(unbanned) > 0 {
-		embed.Title = "✅ Successfully unbanned channels"
-		embed.Description = fmt.Sprintf("List of unbanned channels:\n%v", unbanned)
+		logSettings(s, m, guild, fmt.Sprintf("%v: %v", l.unbannedLog, strings.Join(unbanned, " ")))
+		embed.Title = "✅ Successfully " + l.unbannedTitle
+		embed.Description = strings.Join(unbanned, " ")
 	} else {
-		embed.Title = "❎ Failed to unban channels"
-		embed.Description = fmt.Sprintf("No channels were unbanned")
+		embed.Title = "❎ Nothing changed"
+		embed.Description = fmt.Sprintf("None of the %v were banned.", l.noun)
 	}
 
 	s.ChannelMessageSendEmbed(m.ChannelID, embed)
 	return nil
 }
 
-func blacklist(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
-	ok, err := utils.MemberHasPermission(s, m.GuildID, m.Author.ID, discordgo.PermissionAdministrator|discordgo.PermissionManageServer)
+func resolveChannel(s *discordgo.Session, m *discordgo.MessageCreate, arg string) (string, error) {
+	ch, err := s.Channel(arg)
 	if err != nil {
-		return err
+		switch {
+		case strings.Contains(err.Error(), "403"):
+			return "", fmt.Errorf("Unable to get channel: <#%v>. Not enough permissions.", arg)
+		default:
+			return "", err
+		}
 	}
 
-	if !ok {
-		return fmt.Errorf("You don't have enough permissions to run this command.")
+	if ch.GuildID != m.GuildID {
+		return "", nil
 	}
 
-	if len(args) == 0 {
-		return utils.ErrNotEnoughArguments
+	return ch.ID, nil
+}
+
+func resolveUser(s *discordgo.Session, _ *discordgo.MessageCreate, arg string) (string, error) {
+	user, err := s.User(arg)
+	if err != nil {
+		logrus.Warnf("resolveUser(): %v", err)
+		return "", nil
 	}
 
-	guild := database.GuildCache[m.GuildID]
-	blacklisted := make([]string, 0)
-	for _, arg := range args {
-		arg = strings.Trim(arg, "<@!>")
+	return user.ID, nil
+}
 
-		user, err := s.User(arg)
-		if err != nil {
-			logrus.Warnln("blacklist():", err)
-		}
+// parseBanArgs parses mentions or IDs followed by optional duration and reason. Zero expiry means a permanent ban.
+func parseBanArgs(args []string) ([]string, time.Time, string, error) {
+	targets := make([]string, 0, len(args))
+	for len(args) != 0 && isMentionOrID(args[0]) {
+		targets = append(targets, args[0])
+		args = args[1:]
+	}
 
-		err = database.BanUser(guild.ID, user.ID)
+	if len(targets) == 0 {
+		return nil, time.Time{}, "", utils.ErrNotEnoughArguments
+	}
+
+	// Arguments that look like a duration must be one, so a typo doesn't turn a temporary ban into a permanent one.
+	var expiresAt time.Time
+	if len(args) != 0 && durationLikeRegex.MatchString(args[0]) {
+		d, err := utils.ParseDuration(args[0])
 		if err != nil {
-			logrus.Warnln("blacklist():", err)
+			return nil, time.Time{}, "", err
 		}
 
-		blacklisted = append(blacklisted, fmt.Sprintf("<@%v>", arg))
+		expiresAt = time.Now().Add(d)
+		args = args[1:]
 	}
 
-	embed := utils.BaseEmbed(s)
-	embed.Title = "✅ Successfully blacklisted users"
-	embed.Description = fmt.Sprintf("List of blacklisted users:\n%v", blacklisted)
+	reason := strings.Join(args, " ")
+	if len(reason) > 256 {
+		return nil, time.Time{}, "", errors.New("reason is too long, maximum is 256 characters")
+	}
 
-	s.ChannelMessageSendEmbed(m.ChannelID, embed)
-	return nil
+	return targets, expiresAt, reason, nil
 }
 
-func unblacklist(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
-	ok, err := utils.MemberHasPermission(s, m.GuildID, m.Author.ID, discordgo.PermissionAdministrator|discordgo.PermissionManageServer)
-	if err != nil {
-		return err
+func banDetails(expiresAt time.Time, reason string) []*discordgo.MessageEmbedField {
+	fields := make([]*discordgo.MessageEmbedField, 0, 2)
+	if expiresAt.IsZero() {
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "Expires", Value: "never", Inline: true})
+	} else {
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "Expires", Value: fmt.Sprintf("<t:%v:R>", expiresAt.Unix()), Inline: true})
 	}
 
-	if !ok {
-		return fmt.Errorf("You don't have enough permissions to run this command.")
-	}
-	if len(args) == 0 {
-		return utils.ErrNotEnoughArguments
+	if reason != "" {
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "Reason", Value: reason, Inline: true})
 	}
 
-	guild := database.GuildCache[m.GuildID]
-	unblacklisted := make([]string, 0)
-	for _, arg := range args {
-		arg = strings.Trim(arg, "<@!>")
+	return fields
+}
 
-		exists := false
-		for _, user := range guild.BlacklistedUsers {
-			if arg == user {
-				exists = true
-			}
-		}
+// isMentionOrID reports whether an argument is a user or channel mention or a snowflake ID,
+// so short numbers, e.g. in a reason, aren't mistaken for targets.
+func isMentionOrID(arg string) bool {
+	if strings.HasPrefix(arg, "<") && strings.HasSuffix(arg, ">") {
+		arg = strings.Trim(arg, "<@!#>")
+	} else if len(arg) < 17 || len(arg) > 20 {
+		return false
+	}
 
-		if exists {
-			err = database.UnbanUser(guild.ID, arg)
-			if err != nil {
-				return err
-			}
+	if arg == "" {
+		return false
+	}
 
-			unblacklisted = append(unblacklisted, fmt.Sprintf("<@%v>", arg))
+	for _, r := range arg {
+		if !unicode.IsDigit(r) {
+			return false
 		}
 	}
 
-	embed := utils.BaseEmbed(s)
-	embed.Title = "✅ Successfully unblacklisted users"
-	embed.Description = fmt.Sprintf("List of unblacklisted users:\n%v", unblacklisted)
-
-	s.ChannelMessageSendEmbed(m.ChannelID, embed)
-	return nil
+	return true
 }
 
 func req(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
@@ -360,7 +602,7 @@ func req(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error
 		return utils.ErrNotEnoughArguments
 	}
 
-	g := database.GuildCache[m.GuildID]
+	g := database.GuildCache.Get(m.GuildID)
 
 	channelID := strings.Trim(args[0], "<#>")
 	exists := false
@@ -376,12 +618,28 @@ func req(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error
 		}
 	}
 
+	if args[1] == "unstar" {
+		return reqUnstar(s, m, channelID, args[2:])
+	}
+
+	if args[1] == "maxage" || args[1] == "minage" {
+		return reqAge(s, m, channelID, args[1], args[2:])
+	}
+
+	if args[1] == "starboard" {
+		return reqStarboard(s, m, channelID, args[2:])
+	}
+
+	if args[1] == "color" || args[1] == "colour" {
+		return reqColour(s, m, channelID, args[2:])
+	}
+
 	if args[1] == "default" {
-		g := database.GuildCache[m.GuildID]
+		g := database.GuildCache.Get(m.GuildID)
 
 		f := false
 		for _, ch := range g.ChannelSettings {
-			if ch.ID == channelID {
+			if ch.ID == channelID && ch.StarRequirement > 0 {
 				f = true
 			}
 		}
@@ -391,7 +649,8 @@ func req(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error
 			if err != nil {
 				return err
 			}
-			s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Successfully reset <#%v> settings to defaults", channelID))
+			logSettings(s, m, g, fmt.Sprintf("<#%v> star requirement reset to server's default", channelID))
+			s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Successfully reset <#%v> star requirement to server's default", channelID))
 		} else {
 			s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Can't reset <#%v> to defaults, channel doesn't have star requirements set.", channelID))
 		}
@@ -409,12 +668,188 @@ func req(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error
 		if err != nil {
 			return fmt.Errorf("database error\n%v", err)
 		}
+		logSettings(s, m, g, fmt.Sprintf("<#%v> star requirement set to %v", channelID, stars))
 		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Successfully set <#%v> star requirement to %v", channelID, stars))
 	}
 	return nil
 }
 
+func reqUnstar(s *discordgo.Session, m *discordgo.MessageCreate, channelID string, args []string) error {
+	if len(args) == 0 {
+		return utils.ErrNotEnoughArguments
+	}
+
+	before := database.GuildCache.Get(m.GuildID)
+
+	if args[0] == "default" {
+		err := database.SetChannelUnstar(m.GuildID, channelID, nil)
+		if err != nil {
+			return fmt.Errorf("database error\n%v", err)
+		}
+
+		logSettings(s, m, before, fmt.Sprintf("<#%v> unstar behaviour reset to server's default", channelID))
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Successfully reset <#%v> unstar behaviour to server's default", channelID))
+		return nil
+	}
+
+	policy, err := database.ParseUnstarPolicy(args[0])
+	if err != nil {
+		return err
+	}
+
+	err = database.SetChannelUnstar(m.GuildID, channelID, policy)
+	if err != nil {
+		return fmt.Errorf("database error\n%v", err)
+	}
+
+	logSettings(s, m, before, fmt.Sprintf("<#%v> unstar behaviour set to %v", channelID, policy))
+	s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Successfully set <#%v> unstar behaviour to %v", channelID, policy))
+	return nil
+}
+
+func reqStarboard(s *discordgo.Session, m *discordgo.MessageCreate, channelID string, args []string) error {
+	if len(args) == 0 {
+		return utils.ErrNotEnoughArguments
+	}
+
+	before := database.GuildCache.Get(m.GuildID)
+
+	starboardID := ""
+	if args[0] != "default" {
+		starboardID = strings.Trim(args[0], "<#>")
+		ch, err := s.Channel(starboardID)
+		if err != nil {
+			return err
+		}
+		if ch.GuildID != m.GuildID {
+			return errors.New("can't assign starboard to a channel from a foreign server")
+		}
+		if ch.ID == channelID {
+			return errors.New("channel can't be its own starboard")
+		}
+		if err := checkStarboardChannel(s, ch); err != nil {
+			return err
+		}
+	}
+
+	err := database.SetChannelStarboard(m.GuildID, channelID, starboardID)
+	if err != nil {
+		return fmt.Errorf("database error\n%v", err)
+	}
+
+	if starboardID == "" {
+		logSettings(s, m, before, fmt.Sprintf("<#%v> starboard reset to server's default", channelID))
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Successfully reset <#%v> starboard to server's default", channelID))
+	} else {
+		logSettings(s, m, before, fmt.Sprintf("<#%v> starboard set to <#%v>", channelID, starboardID))
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Successfully set <#%v> starboard to <#%v>", channelID, starboardID))
+	}
+
+	return nil
+}
+
+// checkStarboardChannel returns an error if reposts can't be sent to a channel.
+func checkStarboardChannel(s *discordgo.Session, ch *discordgo.Channel) error {
+	switch ch.Type {
+	case discordgo.ChannelTypeGuildText, discordgo.ChannelTypeGuildNews, discordgo.ChannelTypeGuildForum:
+	default:
+		return errors.New("starboard must be a text, announcement or forum channel")
+	}
+
+	perms, err := s.State.UserChannelPermissions(s.State.User.ID, ch.ID)
+	if err != nil {
+		return err
+	}
+	if perms&discordgo.PermissionSendMessages == 0 || perms&discordgo.PermissionViewChannel == 0 {
+		return fmt.Errorf("can't send messages to <#%v>, check bot's permissions", ch.ID)
+	}
+
+	return nil
+}
+
+func reqColour(s *discordgo.Session, m *discordgo.MessageCreate, channelID string, args []string) error {
+	if len(args) == 0 {
+		return utils.ErrNotEnoughArguments
+	}
+
+	before := database.GuildCache.Get(m.GuildID)
+
+	var colour int64
+	if args[0] != "default" {
+		var err error
+		if colour, err = strconv.ParseInt(args[0], 0, 32); err != nil {
+			if colour, err = strconv.ParseInt("0x"+args[0], 0, 32); err != nil {
+				return fmt.Errorf("unable to parse %v to a number", args[0])
+			}
+		}
+		if colour > 16777215 || colour < 1 {
+			return errors.New("non-existing decimal color, it should be in range from 1 to 16777215")
+		}
+	}
+
+	err := database.SetChannelColour(m.GuildID, channelID, colour)
+	if err != nil {
+		return fmt.Errorf("database error\n%v", err)
+	}
+
+	if colour == 0 {
+		logSettings(s, m, before, fmt.Sprintf("<#%v> color reset to server's default", channelID))
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Successfully reset <#%v> color to server's default", channelID))
+	} else {
+		logSettings(s, m, before, fmt.Sprintf("<#%v> color set to %v", channelID, colour))
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Successfully set <#%v> color to %v", channelID, colour))
+	}
+
+	return nil
+}
+
+func reqAge(s *discordgo.Session, m *discordgo.MessageCreate, channelID, setting string, args []string) error {
+	if len(args) == 0 {
+		return utils.ErrNotEnoughArguments
+	}
+
+	before := database.GuildCache.Get(m.GuildID)
+
+	field := "max_age"
+	if setting == "minage" {
+		field = "min_age"
+	}
+
+	var age *time.Duration
+	switch args[0] {
+	case "default":
+	case "none":
+		none := time.Duration(0)
+		age = &none
+	default:
+		d, err := utils.ParseDuration(args[0])
+		if err != nil {
+			return err
+		}
+		age = &d
+	}
+
+	err := database.SetChannelAge(m.GuildID, channelID, field, age)
+	if err != nil {
+		return fmt.Errorf("database error\n%v", err)
+	}
+
+	if age == nil {
+		logSettings(s, m, before, fmt.Sprintf("<#%v> %v reset to server's default", channelID, setting))
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Successfully reset <#%v> %v to server's default", channelID, setting))
+	} else {
+		logSettings(s, m, before, fmt.Sprintf("<#%v> %v set to %v", channelID, setting, utils.FormatDuration(*age)))
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Successfully set <#%v> %v to %v", channelID, setting, utils.FormatDuration(*age)))
+	}
+
+	return nil
+}
+
 func set(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	if len(args) != 0 && strings.EqualFold(args[0], "template") {
+		return template(s, m, args[1:])
+	}
+
 	switch len(args) {
 	case 0:
 		showGuildSettings(s, m)
@@ -427,7 +862,7 @@ func set(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error
 			return utils.ErrNoPermission
 		}
 
-		setting := args[0]
+		setting := strings.ToLower(args[0])
 		newSetting := strings.ToLower(args[1])
 
 		var passedSetting interface{}
@@ -438,6 +873,12 @@ func set(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error
 			passedSetting, err = strconv.ParseBool(newSetting)
 		case "ignorebots":
 			passedSetting, err = strconv.ParseBool(newSetting)
+		case "webhook":
+			passedSetting, err = strconv.ParseBool(newSetting)
+		case "removebannedstars":
+			passedSetting, err = strconv.ParseBool(newSetting)
+		case "plainmentions":
+			passedSetting, err = strconv.ParseBool(newSetting)
 		case "color":
 			if passedSetting, err = strconv.ParseInt(newSetting, 0, 32); err != nil {
 				if passedSetting, err = strconv.ParseInt("0x"+newSetting, 0, 32); err != nil {
@@ -489,8 +930,63 @@ func set(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error
 			if ch.GuildID != m.GuildID {
 				return errors.New("can't assign starboard to a channel from a foreign server")
 			}
+			if !ch.NSFW {
+				return errors.New("NSFW starboard must be an age-restricted channel")
+			}
 
 			passedSetting = newSetting
+		case "halloffame":
+			if newSetting == "none" {
+				passedSetting = ""
+				break
+			}
+
+			newSetting = strings.Trim(newSetting, "<#>")
+			ch, err := s.Channel(newSetting)
+			if err != nil {
+				return err
+			}
+			if ch.GuildID != m.GuildID {
+				return errors.New("can't assign hall of fame to a channel from a foreign server")
+			}
+
+			passedSetting = newSetting
+		case "logchannel":
+			if newSetting == "none" {
+				passedSetting = ""
+				break
+			}
+
+			newSetting = strings.Trim(newSetting, "<#>")
+			ch, err := s.Channel(newSetting)
+			if err != nil {
+				return err
+			}
+			if ch.GuildID != m.GuildID {
+				return errors.New("can't assign log channel to a channel from a foreign server")
+			}
+
+			passedSetting = newSetting
+		case "maxage", "minage":
+			if newSetting == "none" {
+				passedSetting = time.Duration(0)
+				break
+			}
+
+			passedSetting, err = utils.ParseDuration(newSetting)
+		case "unstar":
+			passedSetting, err = database.ParseUnstarPolicy(newSetting)
+		case "halloffamestars":
+			stars, err := strconv.Atoi(newSetting)
+			if err != nil {
+				return utils.ErrParsingArgument
+			}
+
+			if guild := database.GuildCache.Get(m.GuildID); stars <= guild.MinimumStars {
+				return fmt.Errorf("hall of fame requirement should be greater than starboard requirement (%v)", guild.MinimumStars)
+			}
+
+			passedSetting = stars
 
 		default:
 			return errors.New("unknown setting " + setting)
@@ -500,7 +996,7 @@ func set(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error
 			return err
 		}
 
-		err = changeSetting(m.GuildID, setting, passedSetting)
+		err = changeSetting(s, m, setting, passedSetting)
 		if err != nil {
 			return err
 		}
@@ -513,16 +1009,9 @@ func set(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error
 }
 
 func showGuildSettings(s *discordgo.Session, m *discordgo.MessageCreate) {
-	settings := database.GuildCache[m.GuildID]
+	settings := database.GuildCache.Get(m.GuildID)
 	guild, _ := s.Guild(settings.ID)
 
-	banned := strings.Join(utils.Map(settings.BannedChannels, func(s string) string {
-		return fmt.Sprintf("<#%v>", s)
-	}), " | ")
-	if banned == "" {
-		banned = "none"
-	}
-
 	s.ChannelMessageSendEmbed(m.ChannelID, &discordgo.MessageEmbed{
 		Title:       "Current settings",
 		Description: guild.Name,
@@ -530,7 +1019,7 @@ func showGuildSettings(s *discordgo.Session, m *discordgo.MessageCreate) {
 		Fields: []*discordgo.MessageEmbedField{
 			{
 				Name:  "Starboard",
-				Value: fmt.Sprintf("**%v**\n**Starboard channel:** %v\n**NSFW starboard channel:** %v", utils.FormatBool(settings.Enabled), utils.FormatChannel(settings.StarboardChannel), utils.FormatChannel(settings.NSFWStarboardChannel)),
+				Value: fmt.Sprintf("**%v**\n**Starboard channel:** %v\n**NSFW starboard channel:** %v\n**Hall of fame:** %v (%v stars)\n**Log channel:** %v", utils.FormatBool(settings.Enabled), utils.FormatChannel(settings.StarboardChannel), utils.FormatChannel(settings.NSFWStarboardChannel), utils.FormatChannel(settings.HallOfFameChannel), settings.HallOfFameStars, utils.FormatChannel(settings.LogChannel)),
 			},
 			{
 				Name:  "General settings",
@@ -538,12 +1027,32 @@ func showGuildSettings(s *discordgo.Session, m *discordgo.MessageCreate) {
 			},
 			{
 				Name:  "Behaviour settings",
-				Value: fmt.Sprintf("**Selfstar:** %v | **Ignore bots:** %v | **Min stars:** %v", utils.FormatBool(settings.Selfstar), utils.FormatBool(settings.IgnoreBots), settings.MinimumStars),
+				Value: fmt.Sprintf("**Selfstar:** %v | **Ignore bots:** %v | **Min stars:** %v | **Unstar:** %v | **Webhook mode:** %v | **Plain mentions:** %v", utils.FormatBool(settings.Selfstar), utils.FormatBool(settings.IgnoreBots), settings.MinimumStars, settings.UnstarPolicy(""), utils.FormatBool(settings.WebhookMode), utils.FormatBool(settings.PlainMentions)),
+			},
+			{
+				Name:  "Message age",
+				Value: messageAgeToString(settings),
 			},
 			{
 				Name:  "Unique star requirements",
 				Value: settings.ChannelSettingsToString(),
 			},
+			{
+				Name:  "Star tiers",
+				Value: settings.TiersToString(),
+			},
+			{
+				Name:  "Star roles",
+				Value: settings.StarRolesToString(),
+			},
+			{
+				Name:  "Anti-abuse",
+				Value: antiAbuseToString(settings),
+			},
+			{
+				Name:  "Content filters",
+				Value: fmt.Sprintf("**%v** filters, see ``%vfilters`` | **Report:** %v", len(settings.Filters), settings.Prefix, utils.FormatBool(settings.FilterReport)),
+			},
 			{
 				Name:  "Blacklisted users",
 				Value: settings.BlacklistedToString(),
@@ -552,6 +1061,10 @@ func showGuildSettings(s *discordgo.Session, m *discordgo.MessageCreate) {
 				Name:  "Banned channels",
 				Value: settings.BannedChannelsToString(),
 			},
+			{
+				Name:  "Banned from starring",
+				Value: fmt.Sprintf("%v\n**Remove their stars:** %v", settings.StarBannedToString(), utils.FormatBool(settings.RemoveBannedStars)),
+			},
 		},
 		Thumbnail: &discordgo.MessageEmbedThumbnail{
 			URL: guild.IconURL("320"),
@@ -560,11 +1073,30 @@ func showGuildSettings(s *discordgo.Session, m *discordgo.MessageCreate) {
 	})
 }
 
-func changeSetting(guildID, setting string, newSetting interface{}) error {
-	col := database.DB.Collection("guilds")
+func messageAgeToString(guild *database.Guild) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("**Max age:** %v | **Min age:** %v", utils.FormatDuration(guild.MaxMessageAge), utils.FormatDuration(guild.MinMessageAge)))
+
+	for _, ch := range guild.ChannelSettings {
+		if ch.MaxAge == nil && ch.MinAge == nil {
+			continue
+		}
+
+		min, max := guild.MessageAgeLimits(ch.ID)
+		sb.WriteString(fmt.Sprintf("\n<#%v>: max %v, min %v", ch.ID, utils.FormatDuration(max), utils.FormatDuration(min)))
+	}
+
+	return sb.String()
+}
+
+func changeSetting(s *discordgo.Session, m *discordgo.MessageCreate, setting string, newSetting interface{}) error {
+	var (
+		col    = database.DB.Collection("guilds")
+		before = database.GuildCache.Get(m.GuildID)
+	)
 
 	res := col.FindOneAndUpdate(context.Background(), bson.M{
-		"guild_id": guildID,
+		"guild_id": m.GuildID,
 	}, bson.M{
 		"$set": bson.M{
 			setting:      newSetting,
@@ -578,10 +1110,51 @@ func changeSetting(guildID, setting string, newSetting interface{}) error {
 		return err
 	}
 
-	database.GuildCache[guildID] = guild
+	database.GuildCache.Set(m.GuildID, guild)
+	logSettings(s, m, before, fmt.Sprintf("``%v`` set to ``%v``", setting, settingToString(newSetting)))
 	return nil
 }
 
+// logSettings records changes made since before in guild's audit history and log channel.
+func logSettings(s *discordgo.Session, m *discordgo.MessageCreate, before *database.Guild, details string) {
+	if err := database.RecordChanges(m.Author.ID, before, database.GuildCache.Get(m.GuildID)); err != nil {
+		logrus.Warnln("database.RecordChanges():", err)
+	}
+
+	utils.LogAction(s, &utils.LogEntry{
+		Action:  utils.ActionSettings,
+		GuildID: m.GuildID,
+		UserID:  m.Author.ID,
+		Details: details,
+	})
+}
+
+func settingToString(setting interface{}) string {
+	switch setting := setting.(type) {
+	case time.Duration:
+		return utils.FormatDuration(setting)
+	case *database.EmbedTemplate:
+		if setting == nil {
+			return "default"
+		}
+		return "custom"
+	case []*database.StarTier:
+		return fmt.Sprintf("%v tiers", len(setting))
+	case []*database.ContentFilter:
+		return fmt.Sprintf("%v filters", len(setting))
+	case *database.StarRoles, *database.AntiAbuse:
+		return "custom"
+	case nil:
+		return "none"
+	case string:
+		if setting == "" {
+			return "none"
+		}
+	}
+
+	return fmt.Sprintf("%v", setting)
+}
+
 func invite(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
 	embed := &discordgo.MessageEmbed{
 		Title:       "Thanks for spreading the word!",
@@ -606,7 +1179,7 @@ func setup(s *discordgo.Session, m *discordgo.MessageCreate, args []string) erro
 	}
 
 	var (
-		guild     = database.GuildCache[m.GuildID]
+		guild     = database.GuildCache.Get(m.GuildID)
 		step      = 0
 		done      bool
 		exit      bool
@@ -615,6 +1188,7 @@ func setup(s *discordgo.Session, m *discordgo.MessageCreate, args []string) erro
 		minstars  int
 		emote     string
 		colour    int64
+		maxage    time.Duration
 	)
 
 	verifyChannel := func(chID string) bool {
@@ -816,6 +1390,47 @@ func setup(s *discordgo.Session, m *discordgo.MessageCreate, args []string) erro
 				return true, nil
 			}
 
+			step++
+			return true, nil
+		},
+		func() (bool, error) {
+			embed := utils.BaseEmbed(s)
+			embed.Title = "Eugen Setup | Step 6: Maximum message age"
+			var sb strings.Builder
+			sb.WriteString("**Current settings:**\n")
+			sb.WriteString(fmt.Sprintf("Starboard channel: %v\n", starboard))
+			sb.WriteString(fmt.Sprintf("Minimum stars: %v\n", minstars))
+			sb.WriteString(fmt.Sprintf("Emote: %v\n", emote))
+			sb.WriteString(fmt.Sprintf("Self-star: %v\n", utils.FormatBool(selfstar)))
+			sb.WriteString(fmt.Sprintf("Embed colour: %v\n", colour))
+			sb.WriteString("\nTo complete this step **send a duration like ``7d`` or ``12h``, messages older than that won't be starboarded. Type ``none`` to allow messages of any age.**\n")
+			sb.WriteString("\nType ``cancel`` or ``exit`` to cancel the setup.\nType ``previous`` to come back to a previous step")
+			embed.Description = sb.String()
+
+			res := ""
+			flag := false
+			for !(flag || res == "cancel" || res == "exit" || res == "previous" || res == "none") {
+				res = utils.CreatePrompt(s, m, embed)
+				d, err := utils.ParseDuration(res)
+				if err == nil {
+					flag = true
+					maxage = d
+				}
+			}
+
+			if res == "cancel" || res == "exit" {
+				return false, nil
+			}
+
+			if res == "none" {
+				maxage = 0
+			}
+
+			if res == "previous" {
+				step--
+				return true, nil
+			}
+
 			done = true
 			return true, nil
 		},
@@ -833,16 +1448,20 @@ func setup(s *discordgo.Session, m *discordgo.MessageCreate, args []string) erro
 	}
 
 	if !exit {
+		before := *guild
 		guild.Enabled = true
 		guild.StarboardChannel = strings.Trim(starboard, "<#>")
 		guild.MinimumStars = minstars
 		guild.StarEmote = emote
 		guild.Selfstar = selfstar
 		guild.EmbedColour = colour
+		guild.MaxMessageAge = maxage
 		guild.UpdatedAt = time.Now()
 		err = database.ReplaceGuild(guild)
 		if err != nil {
 			logrus.Warnf("ReplaceGuild(): %v", err)
+		} else {
+			logSettings(s, m, &before, "Setup completed")
 		}
 	}
 
@@ -854,6 +1473,7 @@ func setup(s *discordgo.Session, m *discordgo.MessageCreate, args []string) erro
 			{Name: "Minimum stars", Value: fmt.Sprintf("%v", minstars)},
 			{Name: "Emote", Value: emote},
 			{Name: "Self-star", Value: utils.FormatBool(selfstar)},
+			{Name: "Maximum message age", Value: utils.FormatDuration(maxage)},
 			{Name: "Embed colour", Value: "applied to this embed :)"}}
 		embed.Color = int(colour)
 	} else {
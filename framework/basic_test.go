@@ -0,0 +1,54 @@
+package framework
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseBanArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		targets   []string
+		temporary bool
+		reason    string
+		wantErr   bool
+	}{
+		{"mention", []string{"<@123>"}, []string{"<@123>"}, false, "", false},
+		{"channel and ID", []string{"<#123>", "123456789012345678"}, []string{"<#123>", "123456789012345678"}, false, "", false},
+		{"duration", []string{"<@123>", "7d"}, []string{"<@123>"}, true, "", false},
+		{"duration and reason", []string{"<@123>", "1d12h", "spamming", "stars"}, []string{"<@123>"}, true, "spamming stars", false},
+		{"reason", []string{"<@!123>", "spamming"}, []string{"<@!123>"}, false, "spamming", false},
+		{"reason starting with a number", []string{"<@123>", "5", "alts"}, []string{"<@123>"}, false, "5 alts", false},
+		{"mistyped duration", []string{"<@123>", "7days", "spam"}, nil, false, "", true},
+		{"zero duration", []string{"<@123>", "0d"}, nil, false, "", true},
+		{"no targets", []string{"spamming"}, nil, false, "", true},
+		{"long reason", []string{"<@123>", string(make([]byte, 257))}, nil, false, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targets, expiresAt, reason, err := parseBanArgs(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseBanArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if !reflect.DeepEqual(targets, tt.targets) {
+				t.Errorf("parseBanArgs() targets = %v, want %v", targets, tt.targets)
+			}
+
+			if temporary := !expiresAt.IsZero(); temporary != tt.temporary || (temporary && expiresAt.Before(time.Now())) {
+				t.Errorf("parseBanArgs() expiresAt = %v, want temporary %v", expiresAt, tt.temporary)
+			}
+
+			if reason != tt.reason {
+				t.Errorf("parseBanArgs() reason = %q, want %q", reason, tt.reason)
+			}
+		})
+	}
+}
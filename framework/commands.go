@@ -10,8 +10,17 @@ import (
 var (
 	//CommandGroups stores groups of commands.
 	CommandGroups = make(map[string]CommandGroup)
+	//StarboardModerator is used by moderation commands. It's provided by the starboard on start up.
+	StarboardModerator Moderator
 )
 
+//Moderator lets moderators manually curate the starboard.
+type Moderator interface {
+	Force(s *discordgo.Session, userID, guildID, channelID, messageID string) error
+	Trash(s *discordgo.Session, userID, guildID, channelID, messageID string) error
+	Freeze(s *discordgo.Session, userID, guildID, channelID, messageID string, frozen bool) error
+}
+
 //Command is a structure that defines cmmmand behaviour.
 type Command struct {
 	Name        string
@@ -20,6 +29,8 @@ type Command struct {
 	GuildOnly   bool
 	Exec        func(*discordgo.Session, *discordgo.MessageCreate, []string) error
 	Help        *HelpSettings
+	// RawArgs preserves argument case, e.g. for user-defined text. Command name is always lower case.
+	RawArgs bool
 }
 
 //CommandGroup is a structure that groups similar commands.
@@ -82,6 +93,11 @@ func (c *Command) setGuildOnly(guildOnly bool) *Command {
 	return c
 }
 
+func (c *Command) setRawArgs(raw bool) *Command {
+	c.RawArgs = raw
+	return c
+}
+
 func (c *Command) setExec(exec func(*discordgo.Session, *discordgo.MessageCreate, []string) error) *Command {
 	c.Exec = exec
 	return c
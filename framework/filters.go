@@ -0,0 +1,103 @@
+package framework
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/VTGare/Eugen/database"
+	"github.com/VTGare/Eugen/utils"
+	"github.com/bwmarrin/discordgo"
+)
+
+func filters(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	isAdmin, err := utils.MemberHasPermission(s, m.GuildID, m.Author.ID, discordgo.PermissionAdministrator|discordgo.PermissionManageMessages)
+	if err != nil {
+		return err
+	}
+	if !isAdmin {
+		return utils.ErrNoPermission
+	}
+
+	guild := database.GuildCache.Get(m.GuildID)
+	if len(args) == 0 {
+		embed := utils.BaseEmbed(s)
+		embed.Title = "Content filters"
+		embed.Description = utils.Truncate(guild.FiltersToString(), 4096)
+		embed.Fields = []*discordgo.MessageEmbedField{
+			{Name: "Report to log channel", Value: utils.FormatBool(guild.FilterReport)},
+		}
+		s.ChannelMessageSendEmbed(m.ChannelID, embed)
+		return nil
+	}
+
+	updated := make([]*database.ContentFilter, 0, len(guild.Filters)+1)
+	for _, f := range guild.Filters {
+		filter := *f
+		updated = append(updated, &filter)
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "add":
+		if len(args) < 2 {
+			return utils.ErrNotEnoughArguments
+		}
+
+		filter, err := database.NewContentFilter(strings.ToLower(args[1]), strings.Join(args[2:], " "))
+		if err != nil {
+			return err
+		}
+
+		updated = append(updated, filter)
+	case "remove":
+		if len(args) < 2 {
+			return utils.ErrNotEnoughArguments
+		}
+
+		n, err := strconv.Atoi(args[1])
+		if err != nil {
+			return utils.ErrParsingArgument
+		}
+
+		if n < 1 || n > len(updated) {
+			return fmt.Errorf("filter %v doesn't exist", n)
+		}
+
+		updated = append(updated[:n-1], updated[n:]...)
+	case "clear":
+		updated = make([]*database.ContentFilter, 0)
+	case "report":
+		if len(args) < 2 {
+			return utils.ErrNotEnoughArguments
+		}
+
+		report, err := strconv.ParseBool(args[1])
+		if err != nil {
+			return err
+		}
+
+		if err := changeSetting(s, m, "filterreport", report); err != nil {
+			return err
+		}
+
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Successfully changed ``filterreport`` to ``%v``", report))
+		return nil
+	default:
+		return errors.New("incorrect command usage. Please use e!help filters command for more information")
+	}
+
+	if len(updated) > 50 {
+		return errors.New("too many filters, maximum is 50")
+	}
+
+	if err := changeSetting(s, m, "filters", updated); err != nil {
+		return err
+	}
+
+	embed := utils.BaseEmbed(s)
+	embed.Title = "✅ Successfully updated content filters"
+	embed.Description = utils.Truncate(database.GuildCache.Get(m.GuildID).FiltersToString(), 4096)
+	s.ChannelMessageSendEmbed(m.ChannelID, embed)
+	return nil
+}
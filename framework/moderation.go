@@ -0,0 +1,96 @@
+package framework
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/VTGare/Eugen/utils"
+	"github.com/bwmarrin/discordgo"
+)
+
+func force(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	guildID, channelID, messageID, err := moderationTarget(s, m, args)
+	if err != nil {
+		return err
+	}
+
+	if err := StarboardModerator.Force(s, m.Author.ID, guildID, channelID, messageID); err != nil {
+		return err
+	}
+
+	s.ChannelMessageSend(m.ChannelID, "Successfully posted the message to the starboard.")
+	return nil
+}
+
+func trash(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	guildID, channelID, messageID, err := moderationTarget(s, m, args)
+	if err != nil {
+		return err
+	}
+
+	if err := StarboardModerator.Trash(s, m.Author.ID, guildID, channelID, messageID); err != nil {
+		return err
+	}
+
+	s.ChannelMessageSend(m.ChannelID, "Successfully trashed the message. It won't be reposted to the starboard anymore, use ``force`` to undo.")
+	return nil
+}
+
+func freeze(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	guildID, channelID, messageID, err := moderationTarget(s, m, args)
+	if err != nil {
+		return err
+	}
+
+	if err := StarboardModerator.Freeze(s, m.Author.ID, guildID, channelID, messageID, true); err != nil {
+		return err
+	}
+
+	s.ChannelMessageSend(m.ChannelID, "Successfully froze the starboard post. Its star count won't change anymore.")
+	return nil
+}
+
+func unfreeze(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	guildID, channelID, messageID, err := moderationTarget(s, m, args)
+	if err != nil {
+		return err
+	}
+
+	if err := StarboardModerator.Freeze(s, m.Author.ID, guildID, channelID, messageID, false); err != nil {
+		return err
+	}
+
+	s.ChannelMessageSend(m.ChannelID, "Successfully unfroze the starboard post.")
+	return nil
+}
+
+// moderationTarget checks permissions and parses a message link argument.
+func moderationTarget(s *discordgo.Session, m *discordgo.MessageCreate, args []string) (string, string, string, error) {
+	ok, err := utils.MemberHasPermission(s, m.GuildID, m.Author.ID, discordgo.PermissionAdministrator|discordgo.PermissionManageMessages)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	if !ok {
+		return "", "", "", fmt.Errorf("You don't have enough permissions to run this command.")
+	}
+
+	if len(args) == 0 {
+		return "", "", "", utils.ErrNotEnoughArguments
+	}
+
+	guildID, channelID, messageID, ok := utils.ParseMessageLink(args[0])
+	if !ok {
+		return "", "", "", errors.New("argument should be a message link")
+	}
+
+	if guildID != m.GuildID {
+		return "", "", "", errors.New("message should be from this server")
+	}
+
+	if StarboardModerator == nil {
+		return "", "", "", errors.New("starboard is not ready yet")
+	}
+
+	return guildID, channelID, messageID, nil
+}
@@ -0,0 +1,88 @@
+package framework
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/VTGare/Eugen/database"
+	"github.com/VTGare/Eugen/utils"
+	"github.com/bwmarrin/discordgo"
+)
+
+func starRoles(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	guild := database.GuildCache.Get(m.GuildID)
+
+	if len(args) == 0 {
+		embed := utils.BaseEmbed(s)
+		embed.Title = "Star roles"
+		embed.Description = guild.StarRolesToString()
+		s.ChannelMessageSendEmbed(m.ChannelID, embed)
+		return nil
+	}
+
+	isAdmin, err := utils.MemberHasPermission(s, m.GuildID, m.Author.ID, discordgo.PermissionAdministrator)
+	if err != nil {
+		return err
+	}
+	if !isAdmin {
+		return utils.ErrNoPermission
+	}
+
+	updated := guild.StarRoles.Copy()
+	if args[0] == "clear" {
+		if err := changeSetting(s, m, "starroles", nil); err != nil {
+			return err
+		}
+
+		s.ChannelMessageSend(m.ChannelID, "Successfully cleared star roles. Everyone can star messages again.")
+		return nil
+	}
+
+	if len(args) < 2 {
+		return utils.ErrNotEnoughArguments
+	}
+
+	roleID := strings.Trim(args[1], "<@&>")
+	if _, err := s.State.Role(m.GuildID, roleID); err != nil {
+		return fmt.Errorf("unable to find role %v on this server", args[1])
+	}
+
+	updated.Allow = slices.DeleteFunc(updated.Allow, func(r string) bool { return r == roleID })
+	updated.Deny = slices.DeleteFunc(updated.Deny, func(r string) bool { return r == roleID })
+	updated.Weights = slices.DeleteFunc(updated.Weights, func(w *database.RoleWeight) bool { return w.RoleID == roleID })
+
+	switch args[0] {
+	case "allow":
+		updated.Allow = append(updated.Allow, roleID)
+	case "deny":
+		updated.Deny = append(updated.Deny, roleID)
+	case "weight":
+		if len(args) < 3 {
+			return utils.ErrNotEnoughArguments
+		}
+
+		weight, err := strconv.Atoi(args[2])
+		if err != nil {
+			return utils.ErrParsingArgument
+		}
+
+		if weight < 1 || weight > 10 {
+			return fmt.Errorf("weight should be between 1 and 10, provided weight is %v", weight)
+		}
+
+		updated.Weights = append(updated.Weights, &database.RoleWeight{RoleID: roleID, Weight: weight})
+	case "remove":
+	default:
+		return errors.New("incorrect command usage. Please use e!help starroles command for more information")
+	}
+
+	if err := changeSetting(s, m, "starroles", updated); err != nil {
+		return err
+	}
+
+	s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Successfully updated star roles:\n%v", database.GuildCache.Get(m.GuildID).StarRolesToString()))
+	return nil
+}
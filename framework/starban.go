@@ -0,0 +1,31 @@
+package framework
+
+import (
+	"github.com/VTGare/Eugen/database"
+	"github.com/bwmarrin/discordgo"
+)
+
+var (
+	starBannedUsers = &banList{
+		resolve:  resolveUser,
+		isBanned: (*database.Guild).IsStarBanned,
+		ban:      database.StarBanUser,
+		unban:    database.StarUnbanUser,
+		noun:     "users",
+		trim:     "<@!>",
+		mention:  "<@%v>",
+
+		bannedLog:     "Banned from starring",
+		bannedTitle:   "banned users from starring",
+		unbannedLog:   "Allowed to star",
+		unbannedTitle: "allowed users to star",
+	}
+)
+
+func starBan(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	return starBannedUsers.add(s, m, args)
+}
+
+func starUnban(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	return starBannedUsers.remove(s, m, args)
+}
@@ -0,0 +1,148 @@
+package framework
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/VTGare/Eugen/database"
+	"github.com/VTGare/Eugen/utils"
+	"github.com/bwmarrin/discordgo"
+)
+
+var templateHelp = []*discordgo.MessageEmbedField{
+	{
+		Name:  "Usage",
+		Value: "{prefix}set template ``<part>`` ``<value>``",
+	},
+	{
+		Name:  "Placeholders",
+		Value: "``{author}`` ``{channel}`` ``{count}`` ``{jump}`` ``{timestamp}`` ``{content}``. ``{count}`` is only supported in title and footer.",
+	},
+	{
+		Name:  "title / author / footer",
+		Value: "Sets a part of the embed. Use ``none`` to hide it. Footer is prefixed with the star emote.",
+	},
+	{
+		Name:  "field",
+		Value: "``field <name> | <value>`` adds a field, ``field remove <number>`` removes one, ``field clear`` removes all of them.",
+	},
+	{
+		Name:  "reply / forwarded",
+		Value: "Toggles reply context and forwarded message fields. Accepts ***true*** or ***false***.",
+	},
+	{
+		Name:  "reset",
+		Value: "Restores the default layout.",
+	},
+}
+
+func template(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	isAdmin, err := utils.MemberHasPermission(s, m.GuildID, m.Author.ID, discordgo.PermissionAdministrator)
+	if err != nil {
+		return err
+	}
+	if !isAdmin {
+		return utils.ErrNoPermission
+	}
+
+	if len(args) == 0 {
+		return utils.ErrNotEnoughArguments
+	}
+
+	var (
+		guild = database.GuildCache.Get(m.GuildID)
+		tmpl  = guild.StarboardTemplate().Copy()
+		part  = strings.ToLower(args[0])
+		value = strings.Join(args[1:], " ")
+	)
+
+	if value == "" && part != "reset" {
+		return utils.ErrNotEnoughArguments
+	}
+
+	text := value
+	if strings.EqualFold(value, "none") {
+		text = ""
+	}
+
+	switch part {
+	case "title":
+		tmpl.Title = text
+	case "author":
+		tmpl.Author = text
+	case "footer":
+		tmpl.Footer = text
+	case "field", "fields":
+		switch {
+		case strings.EqualFold(value, "clear"):
+			tmpl.Fields = make([]*database.TemplateField, 0)
+		case strings.EqualFold(args[1], "remove"):
+			if len(args) < 3 {
+				return utils.ErrNotEnoughArguments
+			}
+
+			n, err := strconv.Atoi(args[2])
+			if err != nil {
+				return utils.ErrParsingArgument
+			}
+
+			if n < 1 || n > len(tmpl.Fields) {
+				return fmt.Errorf("field %v doesn't exist", n)
+			}
+
+			tmpl.Fields = append(tmpl.Fields[:n-1], tmpl.Fields[n:]...)
+		default:
+			name, fieldValue, ok := strings.Cut(value, "|")
+			if !ok {
+				return errors.New("field name and value must be separated by ``|``")
+			}
+
+			tmpl.Fields = append(tmpl.Fields, &database.TemplateField{
+				Name:   strings.TrimSpace(name),
+				Value:  strings.TrimSpace(fieldValue),
+				Inline: true,
+			})
+		}
+	case "reply":
+		show, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		tmpl.HideReply = !show
+	case "forwarded":
+		show, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		tmpl.HideForwarded = !show
+	case "reset":
+		tmpl = nil
+	default:
+		return errors.New("unknown template part " + part + ". Please use e!help template for more information")
+	}
+
+	if tmpl != nil {
+		if err := tmpl.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if err := changeSetting(s, m, "template", tmpl); err != nil {
+		return err
+	}
+
+	s.ChannelMessageSendComplex(m.ChannelID, &discordgo.MessageSend{
+		Content: fmt.Sprintf("Successfully changed template ``%v``. Preview:", part),
+		Embeds:  []*discordgo.MessageEmbed{utils.TemplatePreview(database.GuildCache.Get(m.GuildID), m.Author)},
+	})
+	return nil
+}
+
+func preview(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	guild := database.GuildCache.Get(m.GuildID)
+
+	s.ChannelMessageSendEmbed(m.ChannelID, utils.TemplatePreview(guild, m.Author))
+	return nil
+}
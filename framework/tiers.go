@@ -0,0 +1,135 @@
+package framework
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/VTGare/Eugen/database"
+	"github.com/VTGare/Eugen/utils"
+	"github.com/bwmarrin/discordgo"
+)
+
+func tiers(s *discordgo.Session, m *discordgo.MessageCreate, args []string) error {
+	guild := database.GuildCache.Get(m.GuildID)
+
+	if len(args) == 0 {
+		embed := utils.BaseEmbed(s)
+		embed.Title = "Star tiers"
+		embed.Description = guild.TiersToString()
+		s.ChannelMessageSendEmbed(m.ChannelID, embed)
+		return nil
+	}
+
+	isAdmin, err := utils.MemberHasPermission(s, m.GuildID, m.Author.ID, discordgo.PermissionAdministrator)
+	if err != nil {
+		return err
+	}
+	if !isAdmin {
+		return utils.ErrNoPermission
+	}
+
+	updated := make([]*database.StarTier, 0, len(guild.Tiers)+1)
+	for _, t := range guild.Tiers {
+		tier := *t
+		updated = append(updated, &tier)
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "add":
+		if len(args) < 3 {
+			return utils.ErrNotEnoughArguments
+		}
+
+		tier, err := parseTier(s, m.GuildID, args[1:])
+		if err != nil {
+			return err
+		}
+
+		for i, t := range updated {
+			if t.Threshold == tier.Threshold {
+				updated = append(updated[:i], updated[i+1:]...)
+				break
+			}
+		}
+
+		updated = append(updated, tier)
+	case "remove":
+		if len(args) < 2 {
+			return utils.ErrNotEnoughArguments
+		}
+
+		threshold, err := strconv.Atoi(args[1])
+		if err != nil {
+			return utils.ErrParsingArgument
+		}
+
+		found := false
+		for i, t := range updated {
+			if t.Threshold == threshold {
+				updated = append(updated[:i], updated[i+1:]...)
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return fmt.Errorf("there's no tier with %v stars", threshold)
+		}
+	case "clear":
+		updated = make([]*database.StarTier, 0)
+	default:
+		return errors.New("incorrect command usage. Please use e!help tiers command for more information")
+	}
+
+	if len(updated) > 10 {
+		return errors.New("too many tiers, maximum is 10")
+	}
+
+	database.SortTiers(updated)
+	if err := changeSetting(s, m, "tiers", updated); err != nil {
+		return err
+	}
+
+	s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Successfully updated star tiers: %v", database.GuildCache.Get(m.GuildID).TiersToString()))
+	return nil
+}
+
+// parseTier parses <stars> <emoji> [colour] [role] arguments.
+func parseTier(s *discordgo.Session, guildID string, args []string) (*database.StarTier, error) {
+	threshold, err := strconv.Atoi(args[0])
+	if err != nil {
+		return nil, utils.ErrParsingArgument
+	}
+
+	if threshold < 1 {
+		return nil, fmt.Errorf("tier threshold should be >= 1, provided threshold is %v", threshold)
+	}
+
+	tier := &database.StarTier{
+		Threshold: threshold,
+		Emoji:     args[1],
+	}
+
+	for _, arg := range args[2:] {
+		if strings.HasPrefix(arg, "<@&") {
+			roleID := strings.Trim(arg, "<@&>")
+			if _, err := s.State.Role(guildID, roleID); err != nil {
+				return nil, fmt.Errorf("unable to find role %v on this server", arg)
+			}
+
+			tier.RoleID = roleID
+			continue
+		}
+
+		colour, err := strconv.ParseInt(strings.TrimPrefix(arg, "#"), 16, 32)
+		if err != nil || colour > 16777215 || colour < 0 {
+			return nil, fmt.Errorf("unable to parse %v to a colour", arg)
+		}
+
+		tier.Colour = colour
+	}
+
+	return tier, nil
+}
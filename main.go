@@ -7,7 +7,9 @@ import (
 	"syscall"
 
 	"github.com/VTGare/Eugen/database"
+	"github.com/VTGare/Eugen/framework"
 	"github.com/bwmarrin/discordgo"
+	"github.com/jasonlvhit/gocron"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -36,8 +38,12 @@ func main() {
 		discordgo.IntentGuildMessageReactions |
 		discordgo.IntentGuildMessages |
 		discordgo.IntentMessageContent |
+		discordgo.IntentGuildMessagePolls |
 		discordgo.IntentsDirectMessages
 
+	framework.StarboardModerator = moderator{}
+	database.ChannelParent = channelParent
+
 	dg.AddHandler(onReady)
 	dg.AddHandler(messageCreated)
 	dg.AddHandler(guildCreated)
@@ -46,6 +52,17 @@ func main() {
 	dg.AddHandler(reactRemoved)
 	dg.AddHandler(allReactsRemoved)
 	dg.AddHandler(messageDeleted)
+	dg.AddHandler(channelUpdated)
+	dg.AddHandler(messageUpdated)
+	dg.AddHandler(pollVoteAdded)
+	dg.AddHandler(pollVoteRemoved)
+
+	// Temporary bans are lifted in the background once the database is connected.
+	go func() {
+		s := gocron.NewScheduler()
+		s.Every(1).Minute().Do(database.LiftExpiredBans)
+		<-s.Start()
+	}()
 
 	if err := dg.Open(); err != nil {
 		log.Fatalln("Error opening connection,", err)
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	memberCacheTTL = 10 * time.Minute
+	// memberCacheSweep is a number of cached members after which expired entries are swept.
+	memberCacheSweep = 10000
+)
+
+var (
+	memberCache = &members{entries: make(map[string]*cachedMember)}
+)
+
+type members struct {
+	mu      sync.Mutex
+	entries map[string]*cachedMember
+}
+
+type cachedMember struct {
+	roles    []string
+	joinedAt time.Time
+	expires  time.Time
+}
+
+func memberKey(guildID, userID string) string {
+	return guildID + ":" + userID
+}
+
+// store caches a member, e.g. one that came with a reaction event.
+func (c *members) store(guildID, userID string, member *discordgo.Member) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.entries) > memberCacheSweep {
+		for key, m := range c.entries {
+			if time.Now().After(m.expires) {
+				delete(c.entries, key)
+			}
+		}
+	}
+
+	c.entries[memberKey(guildID, userID)] = &cachedMember{roles: member.Roles, joinedAt: member.JoinedAt, expires: time.Now().Add(memberCacheTTL)}
+}
+
+// member returns member's roles and join date from cache, session state or API in that order.
+func (c *members) member(s *discordgo.Session, guildID, userID string) (*cachedMember, error) {
+	c.mu.Lock()
+	m, ok := c.entries[memberKey(guildID, userID)]
+	c.mu.Unlock()
+
+	if ok && time.Now().Before(m.expires) {
+		return m, nil
+	}
+
+	member, err := s.State.Member(guildID, userID)
+	if err != nil {
+		member, err = s.GuildMember(guildID, userID)
+		if err != nil {
+			return nil, fmt.Errorf("GuildMember(): %w", err)
+		}
+	}
+
+	c.store(guildID, userID, member)
+	return &cachedMember{roles: member.Roles, joinedAt: member.JoinedAt}, nil
+}
+
+// reactors returns all users who reacted to a message with an emoji.
+func reactors(s *discordgo.Session, message *discordgo.Message, emoji *discordgo.Emoji) ([]*discordgo.User, error) {
+	users := make([]*discordgo.User, 0)
+	after := ""
+	for {
+		page, err := s.MessageReactions(message.ChannelID, message.ID, emoji.APIName(), 100, "", after)
+		if err != nil {
+			return nil, fmt.Errorf("MessageReactions(): %w", err)
+		}
+
+		users = append(users, page...)
+		if len(page) < 100 {
+			return users, nil
+		}
+
+		after = page[len(page)-1].ID
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var (
+	mentionRegex = regexp.MustCompile(`<(@!?|@&|#)(\d+)>`)
+)
+
+// repostMentions returns allowed mentions of a starboard post. Nothing but given roles is ever pinged,
+// even if reposted content contains @everyone or mentions.
+func repostMentions(roleIDs ...string) *discordgo.MessageAllowedMentions {
+	return &discordgo.MessageAllowedMentions{Roles: roleIDs}
+}
+
+// plainMentions replaces user, role and channel mentions with their names, so they don't render as pills
+// and don't reveal IDs of users who left. @everyone and @here are escaped.
+func plainMentions(s *discordgo.Session, guildID, content string, users []*discordgo.User) string {
+	content = mentionRegex.ReplaceAllStringFunc(content, func(mention string) string {
+		var (
+			match = mentionRegex.FindStringSubmatch(mention)
+			kind  = match[1]
+			id    = match[2]
+		)
+
+		switch kind {
+		case "@&":
+			if role, err := s.State.Role(guildID, id); err == nil {
+				return "@" + role.Name
+			}
+			return "@deleted-role"
+		case "#":
+			if ch, err := s.State.Channel(id); err == nil {
+				if isVoiceChannel(ch) {
+					return "🔊 " + ch.Name
+				}
+				return "#" + ch.Name
+			}
+			return "#unknown-channel"
+		default:
+			if member, err := s.State.Member(guildID, id); err == nil && member.Nick != "" {
+				return "@" + member.Nick
+			}
+
+			for _, user := range users {
+				if user.ID == id {
+					return "@" + displayName(user)
+				}
+			}
+			return "@unknown-user"
+		}
+	})
+
+	return strings.NewReplacer("@everyone", "@\u200beveryone", "@here", "@\u200bhere").Replace(content)
+}
+
+func displayName(user *discordgo.User) string {
+	if user.GlobalName != "" {
+		return user.GlobalName
+	}
+
+	return user.Username
+}
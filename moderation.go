@@ -0,0 +1,188 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/VTGare/Eugen/database"
+	"github.com/VTGare/Eugen/utils"
+	"github.com/bwmarrin/discordgo"
+	"github.com/sirupsen/logrus"
+)
+
+type modAction int
+
+const (
+	actionNone modAction = iota
+	actionForce
+	actionTrash
+	actionFreeze
+	actionUnfreeze
+)
+
+var (
+	errNotStarboarded = errors.New("this message isn't on the starboard")
+	errTrashed        = errors.New("this message was trashed and can't be starboarded")
+	errRepostTarget   = errors.New("this message is a repost, use a link to the original message instead")
+)
+
+// moderator implements framework.Moderator by pushing moderation events to the starboard queue,
+// so they're serialized with reactions on the same message.
+type moderator struct{}
+
+func (moderator) Force(s *discordgo.Session, userID, guildID, channelID, messageID string) error {
+	return moderate(s, userID, guildID, channelID, messageID, actionForce)
+}
+
+func (moderator) Trash(s *discordgo.Session, userID, guildID, channelID, messageID string) error {
+	return moderate(s, userID, guildID, channelID, messageID, actionTrash)
+}
+
+func (moderator) Freeze(s *discordgo.Session, userID, guildID, channelID, messageID string, frozen bool) error {
+	if frozen {
+		return moderate(s, userID, guildID, channelID, messageID, actionFreeze)
+	}
+
+	return moderate(s, userID, guildID, channelID, messageID, actionUnfreeze)
+}
+
+func moderate(s *discordgo.Session, userID, guildID, channelID, messageID string, action modAction) error {
+	guild, ok := database.GuildCache.Lookup(guildID)
+	if !ok {
+		return fmt.Errorf("unknown guild %v", guildID)
+	}
+
+	if guild.StarboardChannel == "" {
+		return errors.New("starboard channel is not set")
+	}
+
+	// Moderators often copy a link of a starboard post, it's resolved to the original message.
+	repost, err := database.RepostByStarboard(channelID, messageID)
+	if err != nil {
+		return err
+	}
+
+	if repost != nil && repost.Original != nil {
+		channelID, messageID = repost.Original.ChannelID, repost.Original.MessageID
+	}
+
+	msg, err := s.ChannelMessage(channelID, messageID)
+	if err != nil {
+		return fmt.Errorf("unable to get the message: %w", err)
+	}
+	msg.GuildID = guildID
+
+	if msg.Author != nil && (msg.Author.ID == s.State.User.ID || isWebhookRepost(msg)) {
+		return errRepostTarget
+	}
+
+	se := &StarboardEvent{
+		guild:   guild,
+		session: s,
+		message: msg,
+		React:   FindReact(msg, guild.StarEmote),
+		action:  action,
+		result:  make(chan error, 1),
+		actorID: userID,
+	}
+
+	starboardQueue.Push(database.NewPair(channelID, messageID), se)
+	return <-se.result
+}
+
+// moderate runs a moderation action. se.board is already resolved.
+func (se *StarboardEvent) moderate() error {
+	switch se.action {
+	case actionForce:
+		if se.board != nil && !se.board.Trashed {
+			return errors.New("this message is already on the starboard")
+		}
+
+		if se.board != nil {
+			if err := database.DeleteMessage(se.board.Original); err != nil {
+				return err
+			}
+		}
+
+		react := se.React
+		if react == nil {
+			react = &discordgo.MessageReactions{Emoji: starEmoji(se.guild.StarEmote)}
+		}
+
+		logrus.Infof("Force posting %v in channel %v", se.message.ID, se.message.ChannelID)
+		return se.post(react, true)
+	case actionTrash:
+		if se.board != nil && se.board.Trashed {
+			return errors.New("this message is already trashed")
+		}
+
+		original := database.NewPair(se.message.ChannelID, se.message.ID)
+		if err := database.TrashMessage(&original, se.guild.ID); err != nil {
+			return err
+		}
+
+		// Record is updated first, so resulting delete events don't drop it.
+		if se.board != nil {
+			logrus.Infof("Trashing starboard %v in channel %v", se.board.Starboard.MessageID, se.board.Starboard.ChannelID)
+			se.deleteReposts()
+		}
+
+		se.logAction(utils.ActionTrash, "")
+		return nil
+	case actionFreeze, actionUnfreeze:
+		if se.board == nil {
+			return errNotStarboarded
+		}
+
+		if se.board.Trashed {
+			return errTrashed
+		}
+
+		if err := database.SetFrozen(se.board.Original, se.action == actionFreeze); err != nil {
+			return err
+		}
+
+		if se.action == actionFreeze {
+			se.logAction(utils.ActionFreeze, "")
+		} else {
+			se.logAction(utils.ActionUnfreeze, "")
+		}
+
+		return nil
+	}
+
+	return nil
+}
+
+// deleteReposts deletes starboard post and all of its copies.
+func (se *StarboardEvent) deleteReposts() {
+	pairs := []*database.MessagePair{se.board.Starboard}
+	for _, c := range se.board.Copies {
+		pairs = append(pairs, c.Message)
+	}
+
+	for _, pair := range pairs {
+		if pair == nil {
+			continue
+		}
+
+		if err := deleteRepost(se.session, pair.ChannelID, pair.MessageID); err != nil {
+			logrus.Warnln("deleteRepost():", err)
+		}
+	}
+}
+
+// starEmoji converts guild's star emote to an emoji, e.g. when the message doesn't have any stars yet.
+func starEmoji(emote string) *discordgo.Emoji {
+	if !strings.HasPrefix(emote, "<") {
+		return &discordgo.Emoji{Name: emote}
+	}
+
+	parts := strings.Split(strings.Trim(emote, "<>"), ":")
+	if len(parts) != 3 {
+		return &discordgo.Emoji{Name: emote}
+	}
+
+	return &discordgo.Emoji{Name: parts[1], ID: parts[2], Animated: parts[0] == "a"}
+}
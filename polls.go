@@ -0,0 +1,274 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/VTGare/Eugen/database"
+	"github.com/VTGare/Eugen/utils"
+	"github.com/bwmarrin/discordgo"
+	"github.com/jasonlvhit/gocron"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// pollFieldPrefix marks an embed field with poll results, so it can be found when results change.
+	pollFieldPrefix = "📊 "
+	// pollMissTTL is how long a poll is remembered as not starboarded.
+	pollMissTTL = 10 * time.Minute
+	// pollRefreshDelay is how long votes are collected before reposts of a poll are updated.
+	pollRefreshDelay = 5 * time.Second
+)
+
+var (
+	polls = &pollTracker{
+		misses:  make(map[database.MessagePair]time.Time),
+		pending: make(map[database.MessagePair]bool),
+	}
+)
+
+func init() {
+	go func() {
+		s := gocron.NewScheduler()
+		s.Every(10).Minutes().Do(polls.sweep)
+		<-s.Start()
+	}()
+}
+
+// pollTracker limits work done on poll votes. It remembers polls that aren't starboarded,
+// so their votes don't query the database, and polls whose reposts are about to be refreshed.
+type pollTracker struct {
+	mu      sync.Mutex
+	misses  map[database.MessagePair]time.Time
+	pending map[database.MessagePair]bool
+}
+
+// isMiss reports whether a poll was recently found not to be starboarded.
+func (c *pollTracker) isMiss(pair database.MessagePair) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	at, ok := c.misses[pair]
+	return ok && time.Since(at) < pollMissTTL
+}
+
+func (c *pollTracker) miss(pair database.MessagePair) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.misses[pair] = time.Now()
+}
+
+// forget is called when a message is starboarded, so its poll results are updated right away.
+func (c *pollTracker) forget(pair database.MessagePair) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.misses, pair)
+}
+
+// schedule marks a poll refresh as pending and returns false if one already is.
+func (c *pollTracker) schedule(pair database.MessagePair) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.pending[pair] {
+		return false
+	}
+
+	c.pending[pair] = true
+	return true
+}
+
+func (c *pollTracker) done(pair database.MessagePair) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.pending, pair)
+}
+
+func (c *pollTracker) sweep() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for pair, at := range c.misses {
+		if time.Since(at) >= pollMissTTL {
+			delete(c.misses, pair)
+		}
+	}
+}
+
+// pollField renders poll's question, answers and current results as an embed field.
+func pollField(poll *discordgo.Poll) *discordgo.MessageEmbedField {
+	var (
+		counts = make(map[int]int)
+		total  = 0
+	)
+
+	if poll.Results != nil {
+		for _, c := range poll.Results.AnswerCounts {
+			counts[c.ID] = c.Count
+			total += c.Count
+		}
+	}
+
+	var sb strings.Builder
+	for _, answer := range poll.Answers {
+		if answer.Media == nil {
+			continue
+		}
+
+		count := counts[answer.AnswerID]
+		percent := 0
+		if total > 0 {
+			percent = count * 100 / total
+		}
+
+		if emoji := pollEmoji(answer.Media.Emoji); emoji != "" {
+			sb.WriteString(emoji + " ")
+		}
+		sb.WriteString(fmt.Sprintf("%v — **%v** (%v%%)\n", answer.Media.Text, count, percent))
+	}
+
+	switch {
+	case poll.Results != nil && poll.Results.Finalized:
+		sb.WriteString(fmt.Sprintf("*Final results, %v votes*", total))
+	case poll.Expiry != nil:
+		sb.WriteString(fmt.Sprintf("*%v votes, ends <t:%v:R>*", total, poll.Expiry.Unix()))
+	default:
+		sb.WriteString(fmt.Sprintf("*%v votes*", total))
+	}
+
+	return &discordgo.MessageEmbedField{
+		Name:  utils.Truncate(pollFieldPrefix+poll.Question.Text, 256),
+		Value: utils.Truncate(sb.String(), 1024),
+	}
+}
+
+// pollEmoji formats an emoji of a poll answer.
+func pollEmoji(emoji *discordgo.ComponentEmoji) string {
+	switch {
+	case emoji == nil:
+		return ""
+	case emoji.ID == "":
+		return emoji.Name
+	case emoji.Animated:
+		return fmt.Sprintf("<a:%v:%v>", emoji.Name, emoji.ID)
+	default:
+		return fmt.Sprintf("<:%v:%v>", emoji.Name, emoji.ID)
+	}
+}
+
+// refreshPoll updates poll results of a repost and its copies.
+func (se *StarboardEvent) refreshPoll() error {
+	if se.board == nil || se.message.Poll == nil {
+		return nil
+	}
+
+	type repost struct {
+		pair      *database.MessagePair
+		webhookID string
+	}
+
+	var (
+		field   = pollField(se.message.Poll)
+		reposts = []repost{{se.board.Starboard, se.board.WebhookID}}
+	)
+
+	for _, c := range se.board.Copies {
+		reposts = append(reposts, repost{c.Message, c.WebhookID})
+	}
+
+	for _, r := range reposts {
+		if r.pair == nil {
+			continue
+		}
+
+		msg, err := se.session.ChannelMessage(r.pair.ChannelID, r.pair.MessageID)
+		if err != nil {
+			logrus.Warnln("se.session.ChannelMessage():", err)
+			continue
+		}
+
+		if len(msg.Embeds) == 0 {
+			continue
+		}
+
+		embed := msg.Embeds[0]
+		for i, f := range embed.Fields {
+			if !strings.HasPrefix(f.Name, pollFieldPrefix) || f.Value == field.Value {
+				continue
+			}
+
+			embed.Fields[i] = field
+			if err := se.editRepost(msg, r.webhookID, embed); err != nil {
+				logrus.Warnln("se.editRepost():", err)
+			}
+			break
+		}
+	}
+
+	return nil
+}
+
+// pushPollRefresh queues an update of poll results shortly after a vote if a poll was starboarded.
+func pushPollRefresh(s *discordgo.Session, guildID, channelID, messageID string) {
+	guild, ok := database.GuildCache.Lookup(guildID)
+	if !ok || !guild.Enabled || guild.StarboardChannel == "" {
+		return
+	}
+
+	// Votes are frequent, so messages are only fetched for starboarded polls
+	// and votes of busy polls are collected into a single refresh.
+	pair := database.NewPair(channelID, messageID)
+	if polls.isMiss(pair) || !polls.schedule(pair) {
+		return
+	}
+
+	time.AfterFunc(pollRefreshDelay, func() {
+		polls.done(pair)
+
+		repost, err := database.Repost(channelID, messageID)
+		if err != nil {
+			logrus.Warnf("pushPollRefresh() -> database.Repost(): %v", err)
+			return
+		}
+
+		if repost == nil {
+			polls.miss(pair)
+			return
+		}
+
+		msg, err := s.ChannelMessage(channelID, messageID)
+		if err != nil {
+			logrus.Warnf("pushPollRefresh() -> s.ChannelMessage(): %v. Channel ID: %v, Message ID: %v", err, channelID, messageID)
+			return
+		}
+
+		if msg.Poll == nil {
+			return
+		}
+
+		msg.GuildID = guildID
+		se := &StarboardEvent{guild: guild, message: msg, session: s, React: FindReact(msg, guild.StarEmote), refresh: true}
+		starboardQueue.Push(pair, se)
+	})
+}
+
+func messageUpdated(s *discordgo.Session, m *discordgo.MessageUpdate) {
+	if m.Poll == nil {
+		return
+	}
+
+	pushPollRefresh(s, m.GuildID, m.ChannelID, m.ID)
+}
+
+func pollVoteAdded(s *discordgo.Session, v *discordgo.MessagePollVoteAdd) {
+	pushPollRefresh(s, v.GuildID, v.ChannelID, v.MessageID)
+}
+
+func pollVoteRemoved(s *discordgo.Session, v *discordgo.MessagePollVoteRemove) {
+	pushPollRefresh(s, v.GuildID, v.ChannelID, v.MessageID)
+}
@@ -23,7 +23,9 @@ func (q Queue) Push(pair database.MessagePair, event *StarboardEvent) {
 		go func() {
 			for e := range q[pair] {
 				err := e.Run()
-				if err != nil {
+				if e.result != nil {
+					e.result <- err
+				} else if err != nil {
 					logrus.Warnln("e.Run(): ", err)
 				}
 			}
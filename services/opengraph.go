@@ -0,0 +1,189 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	// openGraphLimit is the maximum amount of bytes read from a page. Meta tags live in <head>, so it's plenty.
+	openGraphLimit = 512 * 1024
+)
+
+var (
+	openGraphClient = &http.Client{
+		Timeout: 5 * time.Second,
+		Transport: &http.Transport{
+			DialContext: (&net.Dialer{
+				Timeout: 3 * time.Second,
+				Control: publicAddressOnly,
+			}).DialContext,
+			TLSHandshakeTimeout:   3 * time.Second,
+			ResponseHeaderTimeout: 3 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 3 {
+				return errors.New("too many redirects")
+			}
+
+			return nil
+		},
+	}
+
+	metaTagRegex   = regexp.MustCompile(`(?is)<meta\s[^>]*>`)
+	attributeRegex = regexp.MustCompile(`(?is)([a-z:_-]+)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))`)
+	headEndRegex   = regexp.MustCompile(`(?i)</head>`)
+
+	// ErrNotHTML is returned when a page isn't an HTML document.
+	ErrNotHTML = errors.New("not an html document")
+)
+
+// OpenGraphResult is a subset of OpenGraph and Twitter card metadata of a web page.
+type OpenGraphResult struct {
+	URL         string
+	Title       string
+	Description string
+	Image       string
+	SiteName    string
+}
+
+// IsEmpty reports whether a page had no usable metadata.
+func (og *OpenGraphResult) IsEmpty() bool {
+	return og.Title == "" && og.Description == "" && og.Image == ""
+}
+
+// OpenGraph fetches a web page and parses its OpenGraph tags, falling back to Twitter card tags.
+func OpenGraph(uri string) (*OpenGraphResult, error) {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported scheme %v", parsed.Scheme)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, parsed.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; Eugen/1.0; +https://github.com/VTGare/Eugen)")
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+	resp, err := openGraphClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %v", resp.Status)
+	}
+
+	if ct := resp.Header.Get("Content-Type"); ct != "" && !strings.Contains(ct, "html") {
+		return nil, ErrNotHTML
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, openGraphLimit))
+	if err != nil {
+		return nil, err
+	}
+
+	og := parseOpenGraph(string(body))
+	og.URL = resp.Request.URL.String()
+	og.Image = resolveReference(resp.Request.URL, og.Image)
+
+	return og, nil
+}
+
+func parseOpenGraph(page string) *OpenGraphResult {
+	if loc := headEndRegex.FindStringIndex(page); loc != nil {
+		page = page[:loc[0]]
+	}
+
+	tags := make(map[string]string)
+	for _, tag := range metaTagRegex.FindAllString(page, -1) {
+		var key, content string
+		for _, attr := range attributeRegex.FindAllStringSubmatch(tag, -1) {
+			value := attr[2] + attr[3] + attr[4]
+			switch strings.ToLower(attr[1]) {
+			case "property", "name":
+				key = strings.ToLower(value)
+			case "content":
+				content = html.UnescapeString(strings.TrimSpace(value))
+			}
+		}
+
+		if key == "" || content == "" {
+			continue
+		}
+
+		if _, ok := tags[key]; !ok {
+			tags[key] = content
+		}
+	}
+
+	first := func(keys ...string) string {
+		for _, key := range keys {
+			if value, ok := tags[key]; ok {
+				return value
+			}
+		}
+
+		return ""
+	}
+
+	return &OpenGraphResult{
+		Title:       first("og:title", "twitter:title"),
+		Description: first("og:description", "twitter:description", "description"),
+		Image:       first("og:image:secure_url", "og:image", "og:image:url", "twitter:image", "twitter:image:src"),
+		SiteName:    first("og:site_name"),
+	}
+}
+
+func resolveReference(base *url.URL, ref string) string {
+	if ref == "" {
+		return ""
+	}
+
+	parsed, err := url.Parse(ref)
+	if err != nil {
+		return ""
+	}
+
+	resolved := base.ResolveReference(parsed)
+	if resolved.Scheme != "http" && resolved.Scheme != "https" {
+		return ""
+	}
+
+	return resolved.String()
+}
+
+// publicAddressOnly prevents scraping of hosts on private or loopback networks.
+func publicAddressOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return fmt.Errorf("invalid address %v", host)
+	}
+
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() {
+		return fmt.Errorf("refusing to connect to %v", ip)
+	}
+
+	return nil
+}
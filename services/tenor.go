@@ -2,47 +2,70 @@ package services
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io/ioutil"
-	"log"
+	"io"
 	"net/http"
+	"net/url"
 	"os"
+	"regexp"
 	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	tenorEndpoint = "https://tenor.googleapis.com/v2/posts"
+	tenorCacheTTL = 6 * time.Hour
 )
 
 var (
-	tenorAPI string
+	tenorAPI    string
+	tenorClient = &http.Client{Timeout: 5 * time.Second}
+	tenorCache  = newTenorCache(tenorCacheTTL)
+	tenorIDRe   = regexp.MustCompile(`(\d+)$`)
+
+	// ErrTenorNoKey is returned when TENOR_API environment variable isn't set.
+	ErrTenorNoKey = errors.New("tenor api key is not set")
+	// ErrTenorNotFound is returned when Tenor doesn't know about a requested post.
+	ErrTenorNotFound = errors.New("tenor post not found")
+	// ErrTenorInvalidURL is returned when a post ID couldn't be extracted from a URL.
+	ErrTenorInvalidURL = errors.New("not a tenor post url")
 )
 
 func init() {
-	if key, ok := os.LookupEnv("TENOR_API"); ok {
-		tenorAPI = key
-	} else {
-		log.Fatal("TENOR_API environment variable not found")
+	tenorAPI = os.Getenv("TENOR_API")
+}
+
+// TenorError is returned when Tenor API responds with a non-200 status code.
+type TenorError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *TenorError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("tenor api: status %v", e.StatusCode)
 	}
+
+	return fmt.Sprintf("tenor api: status %v: %v", e.StatusCode, e.Message)
 }
 
 type tenorJSON struct {
-	Results []TenorResult
+	Results []*TenorResult `json:"results"`
+	Error   *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
 }
 
 type TenorResult struct {
-	ID    string       `json:"id"`
-	Tags  []string     `json:"tags"`
-	URL   string       `json:"url"`
-	Media []TenorMedia `json:"media"`
-}
-
-type TenorMedia struct {
-	NanoMP4   Media `json:"nanomp4"`
-	NanoWebm  Media `json:"nanowebm"`
-	TinyGIF   Media `json:"tinygif"`
-	TinyMP4   Media `json:"tinymp4"`
-	TinyWebm  Media `json:"tinywebm"`
-	Webm      Media `json:"webm"`
-	GIF       Media `json:"gif"`
-	MP4       Media `json:"mp4"`
-	MediumGIF Media `json:"mediumgif"`
+	ID           string           `json:"id"`
+	Title        string           `json:"title"`
+	Description  string           `json:"content_description"`
+	Tags         []string         `json:"tags"`
+	URL          string           `json:"itemurl"`
+	MediaFormats map[string]Media `json:"media_formats"`
 }
 
 type Media struct {
@@ -53,32 +76,162 @@ type Media struct {
 	Size       int     `json:"size"`
 }
 
-func Tenor(url string) (*TenorResult, error) {
-	if strings.HasPrefix(url, "https://tenor.com/view/") {
-		id := url[strings.LastIndex(url, "-")+1:]
+// MediaURL returns URL of the first available media format, in order of preference.
+func (r *TenorResult) MediaURL(formats ...string) string {
+	for _, format := range formats {
+		if media, ok := r.MediaFormats[format]; ok && media.URL != "" {
+			return media.URL
+		}
+	}
+
+	return ""
+}
+
+// Tenor fetches a Tenor post by any of its public URLs.
+func Tenor(uri string) (*TenorResult, error) {
+	if tenorAPI == "" {
+		return nil, ErrTenorNoKey
+	}
 
-		url = fmt.Sprintf("https://api.tenor.com/v1/gifs?ids=%v&key=%v", id, tenorAPI)
-		resp, err := http.Get(url)
+	id, err := TenorID(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	if res, ok := tenorCache.get(id); ok {
+		return res, nil
+	}
+
+	query := url.Values{}
+	query.Set("ids", id)
+	query.Set("key", tenorAPI)
+	query.Set("client_key", "eugen")
+	query.Set("media_filter", "gif,mediumgif,tinygif,mp4")
+
+	resp, err := tenorClient.Get(tenorEndpoint + "?" + query.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("tenor api: %w", err)
+	}
+	defer resp.Body.Close()
+
+	tenor := &tenorJSON{}
+	if resp.StatusCode != http.StatusOK {
+		tenorErr := &TenorError{StatusCode: resp.StatusCode}
+		if err := json.NewDecoder(io.LimitReader(resp.Body, 64*1024)).Decode(tenor); err == nil && tenor.Error != nil {
+			tenorErr.Message = tenor.Error.Message
+		}
+
+		return nil, tenorErr
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(tenor); err != nil {
+		return nil, fmt.Errorf("tenor api: %w", err)
+	}
+
+	if len(tenor.Results) == 0 {
+		return nil, ErrTenorNotFound
+	}
+
+	res := tenor.Results[0]
+	tenorCache.set(id, res)
+
+	return res, nil
+}
+
+// TenorID extracts a post ID from tenor.com/view/, localised view and short links.
+func TenorID(uri string) (string, error) {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return "", ErrTenorInvalidURL
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(parsed.Host), "www.")
+	if host != "tenor.com" {
+		return "", ErrTenorInvalidURL
+	}
+
+	// Short links (tenor.com/bHs3v.gif) redirect to a full view link.
+	if path := strings.Trim(parsed.Path, "/"); !strings.Contains(path, "/") && path != "" {
+		parsed, err = resolveTenorShortLink(parsed)
 		if err != nil {
-			return nil, err
+			return "", err
+		}
+	}
+
+	segments := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	for i, segment := range segments {
+		if segment != "view" || i+1 >= len(segments) {
+			continue
 		}
-		defer resp.Body.Close()
 
-		if resp.StatusCode == 200 {
-			buf, err := ioutil.ReadAll(resp.Body)
-			if err != nil {
-				return nil, err
-			}
+		if id := tenorIDRe.FindString(segments[i+1]); id != "" {
+			return id, nil
+		}
+	}
+
+	return "", ErrTenorInvalidURL
+}
+
+func resolveTenorShortLink(short *url.URL) (*url.URL, error) {
+	req, err := http.NewRequest(http.MethodHead, short.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := tenorClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("tenor short link: %w", err)
+	}
+	resp.Body.Close()
+
+	return resp.Request.URL, nil
+}
+
+type tenorCacheEntry struct {
+	result    *TenorResult
+	expiresAt time.Time
+}
+
+type tenorLookupCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]tenorCacheEntry
+}
+
+func newTenorCache(ttl time.Duration) *tenorLookupCache {
+	return &tenorLookupCache{
+		ttl:     ttl,
+		entries: make(map[string]tenorCacheEntry),
+	}
+}
+
+func (c *tenorLookupCache) get(id string) (*TenorResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[id]
+	if !ok {
+		return nil, false
+	}
+
+	if time.Now().After(entry.expiresAt) {
+		delete(c.entries, id)
+		return nil, false
+	}
+
+	return entry.result, true
+}
 
-			tenor := &tenorJSON{}
-			err = json.Unmarshal(buf, tenor)
-			if err != nil {
-				return nil, err
-			}
+func (c *tenorLookupCache) set(id string, res *TenorResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-			return &tenor.Results[0], nil
+	now := time.Now()
+	for key, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			delete(c.entries, key)
 		}
 	}
 
-	return nil, nil
+	c.entries[id] = tenorCacheEntry{result: res, expiresAt: now.Add(c.ttl)}
 }
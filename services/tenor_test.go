@@ -0,0 +1,35 @@
+package services
+
+import "testing"
+
+// Short links are resolved over the network, so they're not covered here.
+func TestTenorID(t *testing.T) {
+	tests := []struct {
+		uri     string
+		want    string
+		wantErr bool
+	}{
+		{"https://tenor.com/view/cat-dance-gif-12345678", "12345678", false},
+		{"https://www.tenor.com/view/cat-dance-gif-12345678", "12345678", false},
+		{"https://tenor.com/ja/view/cat-dance-gif-12345678", "12345678", false},
+		{"https://tenor.com/view/cat-dance-gif-12345678?utm_source=share", "12345678", false},
+		{"https://TENOR.com/view/12345678", "12345678", false},
+		{"https://tenor.com/view/cat-dance-gif", "", true},
+		{"https://tenor.com/search/cat-gifs", "", true},
+		{"https://example.com/view/cat-dance-gif-12345678", "", true},
+		{"%zz", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.uri, func(t *testing.T) {
+			got, err := TenorID(tt.uri)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("TenorID(%q) error = %v, wantErr %v", tt.uri, err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("TenorID(%q) = %q, want %q", tt.uri, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	thumbnailLimit   = 8 << 20
+	ffmpegTimeout    = 20 * time.Second
+	discordMediaHost = "media.discordapp.net"
+	discordCDNHost   = "cdn.discordapp.com"
+)
+
+var (
+	thumbnailClient = &http.Client{Timeout: 10 * time.Second}
+
+	// ErrNoThumbnail is returned when none of the extraction methods produced a frame.
+	ErrNoThumbnail = errors.New("unable to extract a video frame")
+
+	// externalProxyHost matches Discord's proxies of images from other websites, e.g. embed thumbnails.
+	externalProxyHost = regexp.MustCompile(`^images-ext-\d+\.discordapp\.net$`)
+
+	// playlistExtensions are formats that make ffmpeg open other URLs or local files.
+	playlistExtensions = []string{".m3u", ".m3u8", ".ffconcat", ".concat", ".txt", ".mpd", ".sdp"}
+)
+
+// VideoThumbnail extracts a still frame from a video. Discord's media proxy is tried first
+// since it's cheap, then a local ffmpeg binary if one is available (FFMPEG_PATH or $PATH).
+func VideoThumbnail(videoURL, proxyURL string) (image.Image, error) {
+	if frame, err := proxyFrame(proxyURL); err == nil {
+		return frame, nil
+	}
+
+	if frame, err := ffmpegFrame(videoURL); err == nil {
+		return frame, nil
+	}
+
+	return nil, ErrNoThumbnail
+}
+
+// PlayOverlay returns a copy of an image with a ▶ button drawn in the middle of it.
+func PlayOverlay(src image.Image) image.Image {
+	var (
+		bounds = src.Bounds()
+		dst    = image.NewRGBA(bounds)
+	)
+
+	draw.Draw(dst, bounds, src, bounds.Min, draw.Src)
+
+	var (
+		radius = min(bounds.Dx(), bounds.Dy()) / 6
+		cx     = bounds.Min.X + bounds.Dx()/2
+		cy     = bounds.Min.Y + bounds.Dy()/2
+		shade  = color.RGBA{0, 0, 0, 150}
+		white  = color.RGBA{255, 255, 255, 255}
+		// Triangle points right, its width is slightly bigger than radius.
+		left   = float64(cx) - float64(radius)*0.35
+		right  = float64(cx) + float64(radius)*0.55
+		height = float64(radius) * 0.5
+	)
+
+	if radius == 0 {
+		return dst
+	}
+
+	for y := cy - radius; y <= cy+radius; y++ {
+		for x := cx - radius; x <= cx+radius; x++ {
+			if !(image.Point{x, y}.In(bounds)) {
+				continue
+			}
+
+			dx, dy := x-cx, y-cy
+			if dx*dx+dy*dy > radius*radius {
+				continue
+			}
+
+			fx, fy := float64(x), float64(y)
+			// Half-height of the triangle shrinks linearly from left edge to its tip.
+			halfHeight := height * (right - fx) / (right - left)
+			if fx >= left && fx <= right && fy >= float64(cy)-halfHeight && fy <= float64(cy)+halfHeight {
+				dst.SetRGBA(x, y, white)
+				continue
+			}
+
+			dst.SetRGBA(x, y, blend(dst.RGBAAt(x, y), shade))
+		}
+	}
+
+	return dst
+}
+
+// EncodePNG encodes an image to a PNG buffer ready to be uploaded.
+func EncodePNG(img image.Image) (*bytes.Buffer, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+
+	return &buf, nil
+}
+
+func proxyFrame(proxyURL string) (image.Image, error) {
+	if proxyURL == "" {
+		return nil, ErrNoThumbnail
+	}
+
+	parsed, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, err
+	}
+
+	// Thumbnails of external videos are already images proxied by images-ext hosts, Discord's own videos need a frame.
+	switch {
+	case parsed.Host == discordMediaHost:
+		query := parsed.Query()
+		query.Set("format", "jpeg")
+		parsed.RawQuery = query.Encode()
+	case !externalProxyHost.MatchString(parsed.Host):
+		return nil, ErrNoThumbnail
+	}
+
+	resp, err := thumbnailClient.Get(parsed.String())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("media proxy: unexpected status %v", resp.Status)
+	}
+
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "image/") {
+		return nil, fmt.Errorf("media proxy: unexpected content type %v", ct)
+	}
+
+	img, _, err := image.Decode(io.LimitReader(resp.Body, thumbnailLimit))
+	return img, err
+}
+
+func ffmpegFrame(videoURL string) (image.Image, error) {
+	if !isDiscordVideo(videoURL) {
+		return nil, ErrNoThumbnail
+	}
+
+	path := os.Getenv("FFMPEG_PATH")
+	if path == "" {
+		var err error
+		if path, err = exec.LookPath("ffmpeg"); err != nil {
+			return nil, err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), ffmpegTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, path,
+		"-hide_banner", "-loglevel", "error",
+		"-protocol_whitelist", "https,tls,tcp",
+		"-i", videoURL,
+		"-frames:v", "1",
+		"-f", "image2pipe", "-vcodec", "png", "-",
+	)
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("ffmpeg: %w", err)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(out))
+	return img, err
+}
+
+// isDiscordVideo reports whether a video is hosted by Discord and isn't a playlist.
+// ffmpeg follows any URL it's given, so it's never run on videos from other websites.
+func isDiscordVideo(videoURL string) bool {
+	parsed, err := url.Parse(videoURL)
+	if err != nil || parsed.Scheme != "https" {
+		return false
+	}
+
+	if parsed.Host != discordCDNHost && parsed.Host != discordMediaHost {
+		return false
+	}
+
+	path := strings.ToLower(parsed.Path)
+	for _, ext := range playlistExtensions {
+		if strings.HasSuffix(path, ext) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func blend(dst, src color.RGBA) color.RGBA {
+	a := uint32(src.A)
+	mix := func(d, s uint8) uint8 {
+		return uint8((uint32(s)*a + uint32(d)*(255-a)) / 255)
+	}
+
+	return color.RGBA{mix(dst.R, src.R), mix(dst.G, src.G), mix(dst.B, src.B), 255}
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	youtubeClient  = &http.Client{Timeout: 5 * time.Second}
+	youtubeIDRegex = regexp.MustCompile(`^[\w-]{11}$`)
+)
+
+type YouTubeVideo struct {
+	ID           string `json:"-"`
+	URL          string `json:"-"`
+	Title        string `json:"title"`
+	AuthorName   string `json:"author_name"`
+	ThumbnailURL string `json:"thumbnail_url"`
+}
+
+// YouTubeID extracts a video ID from watch, youtu.be, shorts and embed links.
+func YouTubeID(uri *url.URL) (string, bool) {
+	var (
+		host = strings.TrimPrefix(strings.ToLower(uri.Host), "www.")
+		path = strings.Trim(uri.Path, "/")
+		id   string
+	)
+
+	switch host {
+	case "youtu.be":
+		id = path
+	case "youtube.com", "m.youtube.com", "music.youtube.com":
+		switch {
+		case path == "watch":
+			id = uri.Query().Get("v")
+		case strings.HasPrefix(path, "shorts/"):
+			id = strings.TrimPrefix(path, "shorts/")
+		case strings.HasPrefix(path, "embed/"):
+			id = strings.TrimPrefix(path, "embed/")
+		case strings.HasPrefix(path, "live/"):
+			id = strings.TrimPrefix(path, "live/")
+		}
+	}
+
+	if i := strings.Index(id, "/"); i != -1 {
+		id = id[:i]
+	}
+
+	if !youtubeIDRegex.MatchString(id) {
+		return "", false
+	}
+
+	return id, true
+}
+
+// YouTube resolves a video by its ID. Thumbnail and canonical URL are always set,
+// title and author are only present if oEmbed endpoint responded.
+func YouTube(id string) (*YouTubeVideo, error) {
+	video := &YouTubeVideo{
+		ID:           id,
+		URL:          "https://www.youtube.com/watch?v=" + id,
+		ThumbnailURL: fmt.Sprintf("https://i.ytimg.com/vi/%v/hqdefault.jpg", id),
+	}
+
+	endpoint := "https://www.youtube.com/oembed?format=json&url=" + url.QueryEscape(video.URL)
+	resp, err := youtubeClient.Get(endpoint)
+	if err != nil {
+		return video, fmt.Errorf("youtube oembed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return video, fmt.Errorf("youtube oembed: unexpected status %v", resp.Status)
+	}
+
+	oembed := &YouTubeVideo{}
+	if err := json.NewDecoder(resp.Body).Decode(oembed); err != nil {
+		return video, fmt.Errorf("youtube oembed: %w", err)
+	}
+
+	video.Title = oembed.Title
+	video.AuthorName = oembed.AuthorName
+	if oembed.ThumbnailURL != "" {
+		video.ThumbnailURL = oembed.ThumbnailURL
+	}
+
+	return video, nil
+}
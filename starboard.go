@@ -2,12 +2,13 @@ package main
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"net/url"
 	"os"
-	"strconv"
 	"strings"
 
 	"github.com/VTGare/Eugen/database"
@@ -19,6 +20,20 @@ import (
 	"mvdan.cc/xurls/v2"
 )
 
+const (
+	// defaultUploadLimit is an attachment size limit for guilds without boosts.
+	defaultUploadLimit int64 = 8 << 20
+	// OpenGraph title and description are cut to these lengths, so they fit in embed's description.
+	openGraphTitleLimit       = 256
+	openGraphDescriptionLimit = 350
+)
+
+var (
+	errFileTooLarge = errors.New("file exceeds upload limit")
+	// errNSFWRepost is returned when a message from an age-restricted channel would be reposted to a channel that isn't.
+	errNSFWRepost = errors.New("messages from age-restricted channels can only be reposted to an age-restricted starboard")
+)
+
 type StarboardEvent struct {
 	React       *discordgo.MessageReactions
 	guild       *database.Guild
@@ -27,8 +42,17 @@ type StarboardEvent struct {
 	board       *database.Message
 	addEvent    *discordgo.MessageReactionAdd
 	removeEvent *discordgo.MessageReactionRemove
+	clearEvent  *discordgo.MessageReactionRemoveAll
 	deleteEvent *discordgo.MessageDelete
-	selfstar    bool
+	// refresh re-renders a repost after its original changes, e.g. when poll results are updated.
+	refresh  bool
+	selfstar bool
+	action   modAction
+	result   chan error
+	// actorID is a moderator who triggered a moderation event.
+	actorID string
+	// counted is set when star count was recomputed from starrers, which already excludes a self-star.
+	counted bool
 }
 
 type StarboardFile struct {
@@ -39,14 +63,14 @@ type StarboardFile struct {
 }
 
 func newStarboardEventAdd(s *discordgo.Session, r *discordgo.MessageReactionAdd, msg *discordgo.Message, emote *discordgo.MessageReactions) (*StarboardEvent, error) {
-	guild := database.GuildCache[r.GuildID]
+	guild := database.GuildCache.Get(r.GuildID)
 	se := &StarboardEvent{guild: guild, message: msg, session: s, addEvent: r, removeEvent: nil, React: emote}
 
 	return se, nil
 }
 
 func newStarboardEventRemove(s *discordgo.Session, r *discordgo.MessageReactionRemove, msg *discordgo.Message) (*StarboardEvent, error) {
-	guild := database.GuildCache[r.GuildID]
+	guild := database.GuildCache.Get(r.GuildID)
 
 	emote := FindReact(msg, guild.StarEmote)
 	se := &StarboardEvent{guild: guild, message: msg, session: s, addEvent: nil, removeEvent: r, React: emote}
@@ -54,8 +78,14 @@ func newStarboardEventRemove(s *discordgo.Session, r *discordgo.MessageReactionR
 	return se, nil
 }
 
+func newStarboardEventCleared(s *discordgo.Session, r *discordgo.MessageReactionRemoveAll, msg *discordgo.Message) (*StarboardEvent, error) {
+	guild := database.GuildCache.Get(r.GuildID)
+
+	return &StarboardEvent{guild: guild, message: msg, session: s, clearEvent: r}, nil
+}
+
 func newStarboardEventDeleted(s *discordgo.Session, d *discordgo.MessageDelete) (*StarboardEvent, error) {
-	guild := database.GuildCache[d.GuildID]
+	guild := database.GuildCache.Get(d.GuildID)
 
 	return &StarboardEvent{guild: guild, message: &discordgo.Message{ID: d.ID, ChannelID: d.ChannelID}, session: s, addEvent: nil, removeEvent: nil, deleteEvent: d}, nil
 }
@@ -68,6 +98,33 @@ func (se *StarboardEvent) Run() error {
 		return err
 	}
 
+	if se.deleteEvent == nil && !se.refresh {
+		if err := se.countVotes(); err != nil {
+			return err
+		}
+	}
+
+	if se.action != actionNone {
+		return se.moderate()
+	}
+
+	if se.board != nil && se.board.Trashed {
+		if se.deleteEvent != nil {
+			return database.DeleteMessage(se.board.Original)
+		}
+
+		return nil
+	}
+
+	// Frozen posts keep their stars, but poll results are still updated.
+	if se.refresh {
+		return se.refreshPoll()
+	}
+
+	if se.board != nil && se.board.Frozen && se.deleteEvent == nil {
+		return nil
+	}
+
 	if se.deleteEvent != nil {
 		se.deleteStarboard()
 	} else if se.isStarboarded() {
@@ -80,7 +137,7 @@ func (se *StarboardEvent) Run() error {
 		switch {
 		case se.addEvent != nil:
 			se.incrementStarboard()
-		case se.removeEvent != nil:
+		case se.removeEvent != nil, se.clearEvent != nil:
 			se.decrementStarboard()
 		}
 	} else if se.addEvent != nil {
@@ -90,12 +147,46 @@ func (se *StarboardEvent) Run() error {
 		}
 		se.selfstar = self
 
-		se.createStarboard()
+		return se.createStarboard()
 	}
 
 	return nil
 }
 
+// actor returns an ID of a user who triggered the event, if known.
+func (se *StarboardEvent) actor() string {
+	switch {
+	case se.actorID != "":
+		return se.actorID
+	case se.addEvent != nil:
+		return se.addEvent.UserID
+	case se.removeEvent != nil:
+		return se.removeEvent.UserID
+	}
+
+	return ""
+}
+
+// logAction records an action in guild's log channel.
+func (se *StarboardEvent) logAction(action, details string) {
+	entry := &utils.LogEntry{
+		Action:  action,
+		GuildID: se.guild.ID,
+		UserID:  se.actor(),
+		Details: details,
+	}
+
+	if se.board != nil {
+		entry.Original = se.board.Original
+		entry.Starboard = se.board.Starboard
+	} else {
+		pair := database.NewPair(se.message.ChannelID, se.message.ID)
+		entry.Original = &pair
+	}
+
+	utils.LogAction(se.session, entry)
+}
+
 func (se *StarboardEvent) isStarboarded() bool {
 	return se.board != nil
 }
@@ -129,7 +220,7 @@ func (se *StarboardEvent) createStarboard() error {
 		return nil
 	}
 
-	if se.selfstar && !se.guild.Selfstar {
+	if se.selfstar && !se.guild.Selfstar && !se.counted {
 		react.Count--
 	}
 
@@ -137,12 +228,74 @@ func (se *StarboardEvent) createStarboard() error {
 		return nil
 	}
 
+	if f := se.guild.MatchFilter(filterContent(se.message)); f != nil {
+		pair := database.NewPair(se.message.ChannelID, se.message.ID)
+		if se.guild.FilterReport && votes.flag(pair, "filter") {
+			se.logAction(utils.ActionFiltered, fmt.Sprintf("Message with %v stars matched %v and wasn't posted.", react.Count, f))
+		}
+
+		return nil
+	}
+
+	err := se.post(react, false)
+	if errors.Is(err, errNSFWRepost) {
+		pair := database.NewPair(se.message.ChannelID, se.message.ID)
+		if votes.flag(pair, "nsfw") {
+			se.logAction(utils.ActionRefused, fmt.Sprintf("Message with %v stars is from an age-restricted channel, but its starboard isn't age-restricted. Set an age-restricted ``nsfwstarboard`` to repost it.", react.Count))
+		}
+
+		return nil
+	}
+
+	return err
+}
+
+// filterContent returns text of a message including forwarded messages, and a number of its mentions for content filters.
+func filterContent(message *discordgo.Message) (string, int) {
+	var (
+		content  = message.Content
+		mentions = len(message.Mentions) + len(message.MentionRoles)
+	)
+
+	for _, snapshot := range message.MessageSnapshots {
+		if snapshot.Message != nil {
+			content += "\n" + snapshot.Message.Content
+		}
+	}
+
+	// @everyone and @here are always mass mentions.
+	if message.MentionEveryone {
+		mentions = math.MaxInt
+	}
+
+	return content, mentions
+}
+
+// post creates a new starboard post regardless of star requirement. Forced posts are never removed because of stars.
+func (se *StarboardEvent) post(react *discordgo.MessageReactions, forced bool) error {
 	ch, err := se.session.Channel(se.message.ChannelID)
 	if err != nil {
 		return err
 	}
 
-	embed, err := createEmbed(se.guild, ch, se.message, react)
+	nsfw, err := isAgeRestricted(se.session, ch.ID)
+	if err != nil {
+		return err
+	}
+
+	starboardChannel := se.guild.Destination(se.message.ChannelID, nsfw)
+	if nsfw {
+		restricted, err := isAgeRestricted(se.session, starboardChannel)
+		if err != nil {
+			return err
+		}
+
+		if !restricted {
+			return errNSFWRepost
+		}
+	}
+
+	embed, err := createEmbed(se.session, se.guild, ch, se.message, react, guildUploadLimit(se.session, se.guild.ID))
 	if err != nil {
 		return err
 	}
@@ -153,36 +306,166 @@ func (se *StarboardEvent) createStarboard() error {
 
 	log := logrus.WithFields(logrus.Fields{
 		"guild":   se.guild.ID,
-		"channel": se.addEvent.ChannelID,
-		"message": se.addEvent.MessageID,
+		"channel": se.message.ChannelID,
+		"message": se.message.ID,
 	})
 
 	log.Debug("creating a new starboard")
 
-	starboardChannel := ""
-	if ch.NSFW && se.guild.NSFWStarboardChannel != "" {
-		starboardChannel = se.guild.NSFWStarboardChannel
-	} else {
-		starboardChannel = se.guild.StarboardChannel
+	tier := se.guild.Tier(react.Count)
+	if tier != nil && tier.RoleID != "" {
+		embed.Content = fmt.Sprintf("<@&%v>", tier.RoleID)
+		embed.AllowedMentions = repostMentions(tier.RoleID)
 	}
 
-	starboard, err := se.session.ChannelMessageSendComplex(starboardChannel, embed)
+	starboard, webhookID, err := se.postRepost(starboardChannel, embed)
 	if err != nil {
 		return err
 	}
 
-	handleError(se.session, se.addEvent.ChannelID, err)
 	oPair := database.NewPair(se.message.ChannelID, se.message.ID)
 	sPair := database.NewPair(starboard.ChannelID, starboard.ID)
-	err = database.InsertOneMessage(database.NewMessage(&oPair, &sPair, se.addEvent.GuildID))
-	handleError(se.session, se.addEvent.ChannelID, err)
+	repost := database.NewMessage(&oPair, &sPair, se.guild.ID)
+	repost.WebhookID = webhookID
+	repost.Forced = forced
+	if tier != nil && tier.RoleID != "" {
+		repost.PingedTier = tier.Threshold
+	}
+	err = database.InsertOneMessage(repost)
+	if err != nil {
+		return err
+	}
+	polls.forget(oPair)
+
+	se.board = repost
+	if forced {
+		se.logAction(utils.ActionForce, "")
+	} else {
+		se.logAction(utils.ActionCreate, fmt.Sprintf("Reached %v stars.", react.Count))
+	}
+
+	se.syncCopies(react)
+
+	return nil
+}
+
+// postRepost sends a starboard post, impersonating the original author in webhook mode.
+// Reposts to forum channels become their own forum posts and are never sent by webhooks.
+func (se *StarboardEvent) postRepost(channelID string, msg *discordgo.MessageSend) (*discordgo.Message, string, error) {
+	board, err := stateChannel(se.session, channelID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if board.Type == discordgo.ChannelTypeGuildForum {
+		sent, err := sendForumRepost(se.session, board, se.message, msg)
+		return sent, "", err
+	}
+
+	if se.guild.WebhookMode && se.message.Author != nil {
+		rewind, err := bufferFiles(msg.Files)
+		if err != nil {
+			return nil, "", err
+		}
+
+		sent, webhookID, err := sendWebhookRepost(se.session, channelID, se.message.Author, msg)
+		if err == nil {
+			return sent, webhookID, nil
+		}
+
+		logrus.Warnf("sendWebhookRepost(): %v. Falling back to a regular message", err)
+		rewind()
+	}
+
+	sent, err := se.session.ChannelMessageSendComplex(channelID, msg)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return sent, "", nil
+}
+
+// syncCopies cross-posts a repost to boards whose threshold it reached,
+// keeps existing copies up to date and demotes it from boards it fell below.
+func (se *StarboardEvent) syncCopies(react *discordgo.MessageReactions) {
+	count := 0
+	if react != nil {
+		count = react.Count
+	}
+
+	for _, promotion := range se.guild.Promotions() {
+		c := se.board.Copy(promotion.Board)
+
+		switch {
+		case c == nil && count >= promotion.Threshold:
+			if err := se.promote(promotion, react); err != nil {
+				logrus.Warnf("se.promote(): %v", err)
+			}
+		case c != nil && count < promotion.Threshold:
+			se.demote(c)
+		case c != nil:
+			msg, err := se.session.ChannelMessage(c.Message.ChannelID, c.Message.MessageID)
+			if err != nil {
+				logrus.Warnln("se.session.ChannelMessage():", err)
+				continue
+			}
+
+			if embed := se.editStarboard(msg, react); embed != nil {
+				if err := se.editRepost(msg, c.WebhookID, embed); err != nil {
+					logrus.Warnln("se.editRepost():", err)
+				}
+			}
+		}
+	}
+}
+
+func (se *StarboardEvent) promote(promotion *database.Promotion, react *discordgo.MessageReactions) error {
+	ch, err := se.session.Channel(se.message.ChannelID)
+	if err != nil {
+		return err
+	}
+
+	embed, err := createEmbed(se.session, se.guild, ch, se.message, react, guildUploadLimit(se.session, se.guild.ID))
+	if err != nil {
+		return err
+	}
 
+	logrus.Infof("Promoting %v to %v in channel %v", se.message.ID, promotion.Board, promotion.ChannelID)
+	sent, webhookID, err := se.postRepost(promotion.ChannelID, embed)
+	if err != nil {
+		return err
+	}
+
+	pair := database.NewPair(sent.ChannelID, sent.ID)
+	err = database.AddCopy(se.board.Original, &database.Copy{
+		Board:     promotion.Board,
+		Message:   &pair,
+		WebhookID: webhookID,
+	})
+	if err != nil {
+		return err
+	}
+
+	se.logAction(utils.ActionPromote, fmt.Sprintf("Promoted to <#%v>.", promotion.ChannelID))
 	return nil
 }
 
+func (se *StarboardEvent) demote(c *database.Copy) {
+	logrus.Infof("Demoting %v from %v", se.board.Original.MessageID, c.Board)
+	if err := database.RemoveCopy(se.board.Original, c.Board); err != nil {
+		logrus.Warnln("database.RemoveCopy():", err)
+	}
+
+	if err := deleteRepost(se.session, c.Message.ChannelID, c.Message.MessageID); err != nil {
+		logrus.Warnln("deleteRepost():", err)
+	}
+
+	se.logAction(utils.ActionDemote, fmt.Sprintf("Removed from <#%v>.", c.Message.ChannelID))
+}
+
 func (se *StarboardEvent) incrementStarboard() {
 	if react := se.React; react != nil {
-		if se.selfstar && !se.guild.Selfstar {
+		if se.selfstar && !se.guild.Selfstar && !se.counted {
 			react.Count--
 		}
 
@@ -201,12 +484,41 @@ func (se *StarboardEvent) incrementStarboard() {
 			embed := se.editStarboard(msg, react)
 			if embed != nil {
 				logrus.Infoln(fmt.Sprintf("Editing starboard (adding) %v in channel %v", msg.ID, msg.ChannelID))
-				se.session.ChannelMessageEditEmbed(msg.ChannelID, msg.ID, embed)
+				if err := se.editRepost(msg, se.board.WebhookID, embed); err != nil {
+					logrus.Warnln("se.editRepost():", err)
+				} else {
+					se.logAction(utils.ActionEdit, fmt.Sprintf("Star added, %v stars.", react.Count))
+				}
 			}
+
+			se.pingTier(msg, react.Count)
+			se.syncCopies(react)
 		}
 	}
 }
 
+// pingTier mentions a tier role once a repost reaches it for the first time.
+func (se *StarboardEvent) pingTier(starboard *discordgo.Message, count int) {
+	tier := se.guild.Tier(count)
+	if tier == nil || tier.RoleID == "" || tier.Threshold <= se.board.PingedTier {
+		return
+	}
+
+	_, err := se.session.ChannelMessageSendComplex(starboard.ChannelID, &discordgo.MessageSend{
+		Content:         fmt.Sprintf("<@&%v> %v This post reached **%v** stars!", tier.RoleID, tier.Emoji, tier.Threshold),
+		Reference:       starboard.Reference(),
+		AllowedMentions: repostMentions(tier.RoleID),
+	})
+	if err != nil {
+		logrus.Warnln("pingTier():", err)
+		return
+	}
+
+	if err := database.SetPingedTier(se.board.Original, tier.Threshold); err != nil {
+		logrus.Warnln("database.SetPingedTier():", err)
+	}
+}
+
 func (se *StarboardEvent) decrementStarboard() {
 	starboard, err := se.session.ChannelMessage(se.board.Starboard.ChannelID, se.board.Starboard.MessageID)
 	if err != nil {
@@ -226,33 +538,68 @@ func (se *StarboardEvent) decrementStarboard() {
 		return
 	}
 
-	required := se.guild.StarsRequired(se.removeEvent.ChannelID)
+	var (
+		required = se.guild.StarsRequired(se.message.ChannelID)
+		policy   = se.guild.UnstarPolicy(se.message.ChannelID)
+	)
+
+	if policy.Frozen() {
+		return
+	}
+
+	// Forced posts ignore star requirement, so they're only updated.
+	if se.board.Forced {
+		policy = &database.UnstarPolicy{Mode: database.UnstarNever}
+	}
+
 	if react := se.React; react != nil {
-		if se.selfstar && !se.guild.Selfstar {
+		if se.selfstar && !se.guild.Selfstar && !se.counted {
 			react.Count--
 		}
 
-		if react.Count <= required/2 {
-			err := se.session.ChannelMessageDelete(starboard.ChannelID, starboard.ID)
+		if policy.ShouldRemove(react.Count, required) {
+			err := deleteRepost(se.session, starboard.ChannelID, starboard.ID)
 			if err != nil {
-				logrus.Warnln("se.session.ChannelMessageDelete():", err)
+				logrus.Warnln("deleteRepost():", err)
+			} else {
+				se.logAction(utils.ActionDelete, fmt.Sprintf("Dropped to %v stars.", react.Count))
 			}
 		} else {
 			embed := se.editStarboard(starboard, react)
 			if embed != nil {
 				logrus.Infof("Editing starboard (subtracting) %v in channel %v", se.board.Starboard.MessageID, se.board.Starboard.ChannelID)
-				_, err := se.session.ChannelMessageEditEmbed(starboard.ChannelID, starboard.ID, embed)
-				if err != nil {
-					logrus.Warnln("se.session.ChannelMessageEditEmbed():", err)
+				if err := se.editRepost(starboard, se.board.WebhookID, embed); err != nil {
+					logrus.Warnln("se.editRepost():", err)
+				} else {
+					se.logAction(utils.ActionEdit, fmt.Sprintf("Star removed, %v stars.", react.Count))
 				}
 			}
 		}
-	} else {
-		err := se.session.ChannelMessageDelete(starboard.ChannelID, starboard.ID)
+	} else if !policy.Keeps() {
+		err := deleteRepost(se.session, starboard.ChannelID, starboard.ID)
 		if err != nil {
-			logrus.Warnln("se.session.ChannelMessageDelete(): ", err)
+			logrus.Warnln("deleteRepost(): ", err)
+		} else {
+			se.logAction(utils.ActionDelete, "All stars were removed.")
 		}
 	}
+
+	se.syncCopies(se.React)
+}
+
+// editRepost replaces starboard embed, going through the webhook-edit endpoint for webhook reposts.
+func (se *StarboardEvent) editRepost(msg *discordgo.Message, webhookID string, embed *discordgo.MessageEmbed) error {
+	if webhookID != "" {
+		return editWebhookRepost(se.session, webhookID, msg.ID, embed)
+	}
+
+	_, err := se.session.ChannelMessageEditComplex(&discordgo.MessageEdit{
+		ID:              msg.ID,
+		Channel:         msg.ChannelID,
+		Embeds:          &[]*discordgo.MessageEmbed{embed},
+		AllowedMentions: repostMentions(),
+	})
+	return err
 }
 
 func (se *StarboardEvent) deleteStarboard() error {
@@ -269,6 +616,13 @@ func (se *StarboardEvent) deleteStarboard() error {
 		} else {
 			return nil
 		}
+
+		// Only a copy was deleted, the starboard post itself is intact.
+		if c := se.board.CopyByMessage(se.message.ID); c != nil {
+			logrus.Infof("Deleting %v copy. ID: %v", c.Board, se.message.ID)
+			se.logAction(utils.ActionDemote, fmt.Sprintf("Post in <#%v> was deleted.", c.Message.ChannelID))
+			return database.RemoveCopy(se.board.Original, c.Board)
+		}
 	}
 
 	if ch, ok := starboardQueue[*se.board.Original]; ok {
@@ -282,72 +636,84 @@ func (se *StarboardEvent) deleteStarboard() error {
 	}
 
 	logrus.Infof("Deleting starboard. ID: %v. Original: %v", se.deleteEvent.ID, original)
+	if original {
+		se.logAction(utils.ActionDelete, "Original message was deleted.")
+	} else {
+		se.logAction(utils.ActionDelete, "Starboard post was deleted.")
+	}
+
+	for _, c := range se.board.Copies {
+		if err := deleteRepost(se.session, c.Message.ChannelID, c.Message.MessageID); err != nil {
+			logrus.Warnln("deleteRepost():", err)
+		}
+	}
+
 	if original {
 		starboard, err := se.session.ChannelMessage(se.board.Starboard.ChannelID, se.board.Starboard.MessageID)
 		if err != nil {
 			return err
 		}
-		err = se.session.ChannelMessageDelete(starboard.ChannelID, starboard.ID)
+		err = deleteRepost(se.session, starboard.ChannelID, starboard.ID)
 		if err != nil {
-			logrus.Warnln("se.session.ChannelMessageDelete():", err)
+			logrus.Warnln("deleteRepost():", err)
 		}
 	}
 	return nil
 }
 
 func createEmbed(
-	guild *database.Guild, ch *discordgo.Channel, message *discordgo.Message,
-	react *discordgo.MessageReactions,
+	s *discordgo.Session, guild *database.Guild, ch *discordgo.Channel, message *discordgo.Message,
+	react *discordgo.MessageReactions, uploadLimit int64,
 ) (*discordgo.MessageSend, error) {
 	var (
-		eb         = embeds.NewBuilder()
-		messageURL = fmt.Sprintf("https://discord.com/channels/%v/%v/%v", message.GuildID, message.ChannelID, message.ID)
-		msg        = &discordgo.MessageSend{}
+		eb  = embeds.NewBuilder()
+		msg = &discordgo.MessageSend{AllowedMentions: repostMentions()}
+		// users are mentioned users whose names are used by plain mentions.
+		users = append([]*discordgo.User{}, message.Mentions...)
 	)
 
-	eb.Author(
-		fmt.Sprintf("@%v in #%v", message.Author.Username, ch.Name),
-		messageURL, message.Author.AvatarURL(""),
-	)
-	eb.Color(int(guild.EmbedColour))
+	tmpl := guild.StarboardTemplate()
+	utils.ApplyTemplate(eb, guild, templateVars(s, guild, ch, message, react), message.Author.AvatarURL(""))
+	eb.Color(guild.Colour(message.ChannelID, react.Count))
 	eb.Timestamp(message.Timestamp)
-	eb.AddField("Original message", fmt.Sprintf("[Click here](%v)", messageURL), true)
-
-	if guild.IsGuildEmoji() {
-		text := fmt.Sprintf("%v", react.Count)
-		eb.Footer(text, emojiURL(react.Emoji))
-	} else {
-		text := fmt.Sprintf("%v %v", "⭐", react.Count)
-		eb.Footer(text, "")
-	}
 
 	var (
 		file          *discordgo.File
 		modifyContent modifyContentFunc
 		content       string
 		err           error
+		poll          = message.Poll
 	)
 
 	if len(message.MessageSnapshots) != 0 {
 		fmsg := message.MessageSnapshots[0].Message
 
 		content = fmsg.Content
-		file, modifyContent, err = messageContent(eb, fmsg)
-
-		eb.AddField("Forwarded message", fmt.Sprintf("[Click here](https://discord.com/channels/%v/%v/%v)",
-			message.MessageReference.GuildID,
-			message.MessageReference.ChannelID,
-			message.MessageReference.MessageID,
-		))
+		poll = fmsg.Poll
+		users = append(users, fmsg.Mentions...)
+		file, modifyContent, err = messageContent(eb, fmsg, uploadLimit)
+
+		if !tmpl.HideForwarded {
+			eb.AddField("Forwarded message", fmt.Sprintf("[Click here](https://discord.com/channels/%v/%v/%v)",
+				message.MessageReference.GuildID,
+				message.MessageReference.ChannelID,
+				message.MessageReference.MessageID,
+			))
+		}
 	} else {
 		content = message.Content
-		file, modifyContent, err = messageContent(eb, message)
+		file, modifyContent, err = messageContent(eb, message, uploadLimit)
 	}
 
 	if err != nil {
 		return nil, err
 	}
 
+	if poll != nil {
+		field := pollField(poll)
+		eb.AddField(field.Name, field.Value)
+	}
+
 	if file != nil {
 		msg.Files = []*discordgo.File{file}
 	}
@@ -356,7 +722,8 @@ func createEmbed(
 		content = modifyContent(content)
 	}
 
-	if message.ReferencedMessage != nil {
+	if message.ReferencedMessage != nil && !tmpl.HideReply {
+		users = append(append(users, message.ReferencedMessage.Author), message.ReferencedMessage.Mentions...)
 		content += "\n\n> Replying to <@" + message.ReferencedMessage.Author.ID + ">"
 		if message.ReferencedMessage.Content != "" {
 			content += "\n> \n> " + message.ReferencedMessage.Content
@@ -370,6 +737,10 @@ func createEmbed(
 		}
 	}
 
+	if guild.PlainMentions {
+		content = plainMentions(s, guild.ID, content, users)
+	}
+
 	eb.Description(content)
 	embed := eb.Finalize()
 	msg.Embeds = []*discordgo.MessageEmbed{embed}
@@ -377,9 +748,31 @@ func createEmbed(
 	return msg, nil
 }
 
+// templateVars collects starboard template placeholder values of a message.
+func templateVars(
+	s *discordgo.Session, guild *database.Guild, ch *discordgo.Channel, message *discordgo.Message, react *discordgo.MessageReactions,
+) *utils.TemplateVars {
+	vars := &utils.TemplateVars{
+		Channel:   channelLabel(s, ch),
+		Count:     react.Count,
+		Jump:      fmt.Sprintf("https://discord.com/channels/%v/%v/%v", guild.ID, message.ChannelID, message.ID),
+		Timestamp: message.Timestamp,
+		Content:   message.Content,
+		Voice:     isVoiceChannel(ch),
+	}
+
+	vars.Emoji, vars.EmojiURL = utils.FooterEmoji(guild, react.Count, emojiURL(react.Emoji))
+
+	if message.Author != nil {
+		vars.Author = message.Author.Username
+	}
+
+	return vars
+}
+
 type modifyContentFunc func(content string) string
 
-func messageContent(eb *embeds.Builder, message *discordgo.Message) (*discordgo.File, modifyContentFunc, error) {
+func messageContent(eb *embeds.Builder, message *discordgo.Message, uploadLimit int64) (*discordgo.File, modifyContentFunc, error) {
 	// Apply sticker first. Anything else will override it.
 	if len(message.StickerItems) != 0 {
 		sticker := message.StickerItems[0]
@@ -389,22 +782,41 @@ func messageContent(eb *embeds.Builder, message *discordgo.Message) (*discordgo.
 
 	// Prioritize attachments over anything else.
 	if len(message.Attachments) != 0 {
-		return fromAttachments(eb, message)
+		return fromAttachments(eb, message, uploadLimit)
 	}
 
-	urls := findURLs(message.Content)
-	if len(urls) != 0 {
-		return fromURL(eb, message, urls[0])
+	var (
+		urls    = findURLs(message.Content)
+		generic *EugenURL
+	)
+
+	for _, url := range urls {
+		if url.Type != URLTypeGeneric {
+			return fromURL(eb, message, url, uploadLimit)
+		}
+
+		if generic == nil {
+			generic = url
+		}
 	}
 
-	if len(message.Embeds) != 0 {
+	if len(message.Embeds) != 0 && isUsableEmbed(message.Embeds[0]) {
 		return fromEmbed(eb, message.Embeds[0])
 	}
 
+	if generic != nil {
+		return fromOpenGraph(eb, generic)
+	}
+
 	return nil, nil, nil
 }
 
-func fromAttachments(eb *embeds.Builder, message *discordgo.Message) (*discordgo.File, modifyContentFunc, error) {
+// isUsableEmbed reports whether Discord's own embed carries anything worth reposting.
+func isUsableEmbed(embed *discordgo.MessageEmbed) bool {
+	return embed.Image != nil || embed.Thumbnail != nil || embed.Video != nil || embed.Description != ""
+}
+
+func fromAttachments(eb *embeds.Builder, message *discordgo.Message, uploadLimit int64) (*discordgo.File, modifyContentFunc, error) {
 	var (
 		first = message.Attachments[0]
 		rest  = message.Attachments[1:]
@@ -414,14 +826,19 @@ func fromAttachments(eb *embeds.Builder, message *discordgo.Message) (*discordgo
 	if utils.ImageURLRegex.MatchString(first.URL) {
 		eb.Image(first.URL)
 	} else {
-		var err error
-		file, err = downloadFile(first.URL)
-		if err != nil {
-			return nil, nil, err
+		if first.Size <= int(uploadLimit) {
+			var err error
+			file, err = downloadFile(first.URL, uploadLimit)
+			if err != nil {
+				return nil, nil, err
+			}
 		}
 
 		if file == nil {
 			eb.AddField("Attachment", fmt.Sprintf("[Click here](%v)", first.URL), true)
+			if isVideo(first.ContentType, first.Filename) {
+				file = videoThumbnail(eb, first.URL, first.ProxyURL)
+			}
 		}
 	}
 
@@ -432,7 +849,7 @@ func fromAttachments(eb *embeds.Builder, message *discordgo.Message) (*discordgo
 	return file, nil, nil
 }
 
-func fromURL(eb *embeds.Builder, message *discordgo.Message, url *EugenURL) (*discordgo.File, modifyContentFunc, error) {
+func fromURL(eb *embeds.Builder, message *discordgo.Message, url *EugenURL, uploadLimit int64) (*discordgo.File, modifyContentFunc, error) {
 	removeURL := func(content string) string {
 		return strings.Replace(content, url.URL.String(), "", 1)
 	}
@@ -448,34 +865,66 @@ func fromURL(eb *embeds.Builder, message *discordgo.Message, url *EugenURL) (*di
 			uri = strings.Replace(uri, "gifv", "mp4", 1)
 		}
 
-		file, err := downloadFile(uri)
+		file, err := downloadFile(uri, uploadLimit)
 		if err != nil {
 			return nil, nil, err
 		}
 
 		if file == nil {
 			eb.AddField("Attachment", fmt.Sprintf("[Click here](%v)", uri), true)
+
+			// Discord generates thumbnails for embedded videos and proxies its own CDN.
+			proxyURL := ""
+			if len(message.Embeds) != 0 && message.Embeds[0].Thumbnail != nil {
+				proxyURL = message.Embeds[0].Thumbnail.ProxyURL
+			} else if url.URL.Host == "cdn.discordapp.com" {
+				proxyURL = strings.Replace(uri, "cdn.discordapp.com", "media.discordapp.net", 1)
+			}
+
+			file = videoThumbnail(eb, uri, proxyURL)
 		}
 
 		return file, removeURL, nil
 	}
 
 	if url.Type == URLTypeTenor {
-		uri := url.URL.String()
-		res, err := services.Tenor(uri)
+		res, err := services.Tenor(url.URL.String())
 		if err != nil {
-			return nil, nil, fmt.Errorf("tenor error: %w", err)
+			if !errors.Is(err, services.ErrTenorNoKey) {
+				logrus.Warnf("services.Tenor(): %v", err)
+			}
+
+			return fromTenorEmbed(eb, message)
 		}
 
-		// Do nothing.
-		if len(res.Media) == 0 {
-			return nil, nil, nil
+		media := res.MediaURL("gif", "mediumgif", "tinygif")
+		if media == "" {
+			return fromTenorEmbed(eb, message)
 		}
 
-		eb.Image(res.Media[0].MediumGIF.URL)
+		eb.Image(media)
 		return nil, removeURL, nil
 	}
 
+	if url.Type == URLTypeYouTube {
+		id, ok := services.YouTubeID(url.URL)
+		if !ok {
+			return nil, nil, nil
+		}
+
+		video, err := services.YouTube(id)
+		if err != nil {
+			logrus.Warnf("services.YouTube(): %v", err)
+		}
+
+		eb.Image(video.ThumbnailURL)
+		if video.Title != "" {
+			eb.AddField("Watch on YouTube", fmt.Sprintf("[%v](%v)", video.Title, url.URL.String()), true)
+		}
+
+		return nil, nil, nil
+	}
+
 	if url.Type == URLTypeImgur {
 		eb.Image(fmt.Sprintf("https://i.imgur.com/%v.png", url.URL.Path))
 		if len(message.Embeds) == 0 {
@@ -492,6 +941,67 @@ func fromURL(eb *embeds.Builder, message *discordgo.Message, url *EugenURL) (*di
 	return nil, nil, nil
 }
 
+// fromTenorEmbed falls back to Discord's own Tenor embed thumbnail when Tenor API is unavailable.
+func fromTenorEmbed(eb *embeds.Builder, message *discordgo.Message) (*discordgo.File, modifyContentFunc, error) {
+	if len(message.Embeds) == 0 {
+		return nil, nil, nil
+	}
+
+	embed := message.Embeds[0]
+	switch {
+	case embed.Thumbnail != nil:
+		eb.Image(embed.Thumbnail.ProxyURL)
+	case embed.Image != nil:
+		eb.Image(embed.Image.ProxyURL)
+	}
+
+	return nil, nil, nil
+}
+
+func fromOpenGraph(eb *embeds.Builder, url *EugenURL) (*discordgo.File, modifyContentFunc, error) {
+	og, err := services.OpenGraph(url.URL.String())
+	if err != nil {
+		logrus.Warnf("services.OpenGraph(): %v", err)
+		return nil, nil, nil
+	}
+
+	if og.IsEmpty() {
+		return nil, nil, nil
+	}
+
+	if og.Image != "" {
+		eb.Image(og.Image)
+	}
+
+	var (
+		title       = utils.Truncate(og.Title, openGraphTitleLimit)
+		siteName    = utils.Truncate(og.SiteName, openGraphTitleLimit)
+		description = utils.Truncate(og.Description, openGraphDescriptionLimit)
+	)
+
+	contentFunc := func(content string) string {
+		if title == "" && description == "" {
+			return content
+		}
+
+		content += "\n\n"
+
+		if title != "" {
+			content += fmt.Sprintf("> %v", title)
+		} else if siteName != "" {
+			content += fmt.Sprintf("> %v", siteName)
+		}
+
+		if description != "" {
+			content += "\n> \n> " + strings.ReplaceAll(description, "\n", "\n> ")
+		}
+
+		return content
+	}
+
+	return nil, contentFunc, nil
+}
+
 func fromEmbed(eb *embeds.Builder, embed *discordgo.MessageEmbed) (*discordgo.File, modifyContentFunc, error) {
 	if embed.Image != nil {
 		eb.Image(embed.Image.URL)
@@ -544,17 +1054,20 @@ func findURLs(content string) []*EugenURL {
 			URL: parsed,
 		}
 
+		// Tenor short links end with .gif, so they're checked before images.
 		switch {
+		case strings.TrimPrefix(parsed.Host, "www.") == "tenor.com":
+			eu.Type = URLTypeTenor
 		case hasSuffixes(parsed.Path, "jpg", "png", "jpeg", "webp", "gif"):
 			eu.Type = URLTypeImage
 		case hasSuffixes(parsed.Path, "mp4", "webm", "mov", "gifv"):
 			eu.Type = URLTypeVideo
+		case utils.YoutubeRegex.MatchString(uri):
+			eu.Type = URLTypeYouTube
 		case strings.Contains(parsed.Host, "imgur"):
 			eu.Type = URLTypeImgur
-		case strings.Contains(parsed.String(), "tenor.com/view"):
-			eu.Type = URLTypeTenor
 		default:
-			continue
+			eu.Type = URLTypeGeneric
 		}
 
 		urls = append(urls, eu)
@@ -575,26 +1088,68 @@ func FindReact(message *discordgo.Message, emote string) *discordgo.MessageReact
 func (se *StarboardEvent) editStarboard(msg *discordgo.Message, react *discordgo.MessageReactions) *discordgo.MessageEmbed {
 	embed := msg.Embeds[0]
 
-	current, _ := strconv.Atoi(strings.Trim(embed.Footer.Text, "⭐ "))
-	if current == react.Count {
+	ch, err := stateChannel(se.session, se.message.ChannelID)
+	if err != nil {
+		logrus.Warnln("stateChannel():", err)
 		return nil
 	}
 
-	if se.guild.IsGuildEmoji() {
-		embed.Footer.Text = strconv.Itoa(react.Count)
-	} else {
-		embed.Footer.Text = fmt.Sprintf("⭐ %v", react.Count)
-	}
+	var (
+		vars   = templateVars(se.session, se.guild, ch, se.message, react)
+		footer = utils.TemplateFooter(se.guild, vars)
+		title  = utils.TemplateTitle(se.guild, vars)
+		colour = se.guild.Colour(se.message.ChannelID, react.Count)
+	)
 
 	if se.selfstar && se.guild.Selfstar {
-		embed.Footer.Text += " | self-starred"
+		footer += " | self-starred"
+	}
+
+	if embed.Footer != nil && embed.Footer.Text == footer && embed.Footer.IconURL == vars.EmojiURL &&
+		embed.Title == title && embed.Color == colour {
+		return nil
 	}
 
+	embed.Footer = &discordgo.MessageEmbedFooter{Text: footer, IconURL: vars.EmojiURL}
+	embed.Title = title
+	embed.Color = colour
+
 	return embed
 }
 
-func downloadFile(uri string) (*discordgo.File, error) {
-	allowed, err := checkFilesizeLimit(uri)
+// videoThumbnail extracts a still frame from a video that couldn't be reuploaded,
+// sets it as an embed image and returns it as a file to attach.
+func videoThumbnail(eb *embeds.Builder, videoURL, proxyURL string) *discordgo.File {
+	frame, err := services.VideoThumbnail(videoURL, proxyURL)
+	if err != nil {
+		logrus.Debugf("services.VideoThumbnail(): %v", err)
+		return nil
+	}
+
+	buf, err := services.EncodePNG(services.PlayOverlay(frame))
+	if err != nil {
+		logrus.Warnf("services.EncodePNG(): %v", err)
+		return nil
+	}
+
+	eb.Image("attachment://thumbnail.png")
+	return &discordgo.File{
+		Name:        "thumbnail.png",
+		ContentType: "image/png",
+		Reader:      buf,
+	}
+}
+
+func isVideo(contentType, filename string) bool {
+	if strings.HasPrefix(contentType, "video/") {
+		return true
+	}
+
+	return hasSuffixes(strings.ToLower(filename), "mp4", "webm", "mov")
+}
+
+func downloadFile(uri string, limit int64) (*discordgo.File, error) {
+	allowed, err := checkFilesizeLimit(uri, limit)
 	if err != nil {
 		return nil, fmt.Errorf("filesize limit: %w", err)
 	}
@@ -603,8 +1158,12 @@ func downloadFile(uri string) (*discordgo.File, error) {
 		return nil, nil
 	}
 
-	content, filename, err := getFile(uri)
+	content, filename, err := getFile(uri, limit)
 	if err != nil {
+		if errors.Is(err, errFileTooLarge) {
+			return nil, nil
+		}
+
 		return nil, err
 	}
 
@@ -614,19 +1173,38 @@ func downloadFile(uri string) (*discordgo.File, error) {
 	}, nil
 }
 
-func checkFilesizeLimit(uri string) (bool, error) {
-	var limit int64 = 8388608
+// guildUploadLimit returns maximum attachment size in bytes for guild's boost tier.
+func guildUploadLimit(s *discordgo.Session, guildID string) int64 {
+	guild, err := s.State.Guild(guildID)
+	if err != nil {
+		return defaultUploadLimit
+	}
+
+	switch guild.PremiumTier {
+	case discordgo.PremiumTier2:
+		return 50 << 20
+	case discordgo.PremiumTier3:
+		return 100 << 20
+	default:
+		return defaultUploadLimit
+	}
+}
 
+// checkFilesizeLimit rejects files that are known to be too large beforehand.
+// Unknown content length (chunked responses) is allowed and enforced by getFile.
+func checkFilesizeLimit(uri string, limit int64) (bool, error) {
 	head, err := http.Head(uri)
 	if err != nil {
 		return false, fmt.Errorf("http head: %w", err)
 	}
+	head.Body.Close()
 
 	return head.ContentLength < limit, nil
 }
 
 // download file downloads a file from URL and returns its contents and filename.
-func getFile(uri string) (*bytes.Buffer, string, error) {
+// Returns errFileTooLarge as soon as more than limit bytes are received.
+func getFile(uri string, limit int64) (*bytes.Buffer, string, error) {
 	var filename string
 
 	lastSlash := strings.LastIndex(uri, "/")
@@ -647,12 +1225,24 @@ func getFile(uri string) (*bytes.Buffer, string, error) {
 
 	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("http get: unexpected status %v", resp.Status)
+	}
+
+	if resp.ContentLength >= limit {
+		return nil, "", errFileTooLarge
+	}
+
 	var buf bytes.Buffer
-	_, err = io.Copy(&buf, resp.Body)
+	n, err := io.Copy(&buf, io.LimitReader(resp.Body, limit))
 	if err != nil {
 		return nil, "", fmt.Errorf("io copy: %w", err)
 	}
 
+	if n >= limit {
+		return nil, "", errFileTooLarge
+	}
+
 	return &buf, filename, nil
 }
 
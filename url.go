@@ -9,10 +9,12 @@ const (
 	URLTypeVideo
 	URLTypeTenor
 	URLTypeImgur
+	URLTypeYouTube
+	URLTypeGeneric
 )
 
 func (t URLType) String() string {
-	return [...]string{"Image", "Video", "Tenor", "Imgur"}[t]
+	return [...]string{"Image", "Video", "Tenor", "Imgur", "YouTube", "Generic"}[t]
 }
 
 type EugenURL struct {
@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"fmt"
+
+	"github.com/VTGare/Eugen/database"
+	"github.com/bwmarrin/discordgo"
+	log "github.com/sirupsen/logrus"
+)
+
+// Actions recorded in guild's log channel.
+const (
+	ActionCreate   = "Starboard post created"
+	ActionEdit     = "Starboard post edited"
+	ActionDelete   = "Starboard post deleted"
+	ActionForce    = "Message force posted"
+	ActionTrash    = "Message trashed"
+	ActionFreeze   = "Starboard post frozen"
+	ActionUnfreeze = "Starboard post unfrozen"
+	ActionPromote  = "Starboard post promoted"
+	ActionDemote   = "Starboard post demoted"
+	ActionSettings = "Settings changed"
+	ActionFlagged  = "Suspicious stars"
+	ActionFiltered = "Message filtered"
+	ActionRefused  = "Repost refused"
+	ActionError    = "Error"
+)
+
+var actionColours = map[string]int{
+	ActionCreate:   0x43b581,
+	ActionForce:    0x43b581,
+	ActionPromote:  0x43b581,
+	ActionEdit:     0x7289da,
+	ActionFreeze:   0x7289da,
+	ActionUnfreeze: 0x7289da,
+	ActionSettings: 0x7289da,
+	ActionDelete:   0xfaa61a,
+	ActionDemote:   0xfaa61a,
+	ActionFlagged:  0xfaa61a,
+	ActionFiltered: 0xfaa61a,
+	ActionTrash:    0xf04747,
+	ActionRefused:  0xf04747,
+	ActionError:    0xf04747,
+}
+
+// LogEntry is a structured record of something that happened to a guild's starboard.
+type LogEntry struct {
+	Action  string
+	GuildID string
+	// UserID is an ID of a user who triggered the action. Empty if unknown, e.g. for deletions.
+	UserID    string
+	Original  *database.MessagePair
+	Starboard *database.MessagePair
+	Details   string
+}
+
+// LogAction posts an entry to guild's log channel if one is set.
+func LogAction(s *discordgo.Session, entry *LogEntry) {
+	guild, ok := database.GuildCache.Lookup(entry.GuildID)
+	if !ok || guild.LogChannel == "" {
+		return
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       entry.Action,
+		Description: entry.Details,
+		Color:       actionColours[entry.Action],
+		Timestamp:   EmbedTimestamp(),
+	}
+
+	if entry.UserID != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Triggered by", Value: fmt.Sprintf("<@%v>", entry.UserID), Inline: true})
+	}
+
+	if entry.Original != nil {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Original message", Value: fmt.Sprintf("[Jump](%v)", MessageLink(entry.GuildID, entry.Original)), Inline: true})
+	}
+
+	if entry.Starboard != nil {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Starboard post", Value: fmt.Sprintf("[Jump](%v)", MessageLink(entry.GuildID, entry.Starboard)), Inline: true})
+	}
+
+	_, err := s.ChannelMessageSendComplex(guild.LogChannel, &discordgo.MessageSend{
+		Embeds:          []*discordgo.MessageEmbed{embed},
+		AllowedMentions: &discordgo.MessageAllowedMentions{},
+	})
+	if err != nil {
+		log.Warnf("LogAction(): %v", err)
+	}
+}
+
+// MessageLink returns a jump link to a message.
+func MessageLink(guildID string, pair *database.MessagePair) string {
+	return fmt.Sprintf("https://discord.com/channels/%v/%v/%v", guildID, pair.ChannelID, pair.MessageID)
+}
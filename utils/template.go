@@ -0,0 +1,172 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/VTGare/Eugen/database"
+	"github.com/VTGare/embeds"
+	"github.com/bwmarrin/discordgo"
+)
+
+// Discord's embed limits, expanded template parts are cut to them.
+const (
+	embedTitleLimit      = 256
+	embedFieldNameLimit  = 256
+	embedFieldValueLimit = 1024
+	embedFooterLimit     = 2048
+)
+
+// TemplateVars holds values substituted into starboard template placeholders.
+type TemplateVars struct {
+	Author    string
+	Channel   string
+	Count     int
+	Jump      string
+	Timestamp time.Time
+	Content   string
+	// Voice labels a channel with a speaker instead of #, e.g. for text chats of voice channels.
+	Voice bool
+	// Emoji prefixes footer text, EmojiURL is used as footer icon for custom emotes.
+	Emoji    string
+	EmojiURL string
+}
+
+// Expand replaces all placeholders in text.
+func (v *TemplateVars) Expand(text string) string {
+	content := v.Content
+	if runes := []rune(content); len(runes) > 1000 {
+		content = string(runes[:1000]) + "…"
+	}
+
+	prefix := "#"
+	if v.Voice {
+		prefix = "🔊 "
+	}
+
+	return strings.NewReplacer(
+		"{author}", v.Author,
+		"#{channel}", prefix+v.Channel,
+		"{channel}", v.Channel,
+		"{count}", strconv.Itoa(v.Count),
+		"{jump}", v.Jump,
+		"{timestamp}", v.Timestamp.UTC().Format("Jan 2, 2006 15:04 UTC"),
+		"{content}", content,
+	).Replace(text)
+}
+
+// ApplyTemplate sets embed's title, author, fields and footer from guild's template.
+func ApplyTemplate(eb *embeds.Builder, guild *database.Guild, vars *TemplateVars, avatarURL string) {
+	tmpl := guild.StarboardTemplate()
+
+	if tmpl.Title != "" {
+		eb.Title(TemplateTitle(guild, vars))
+	}
+
+	if tmpl.Author != "" {
+		eb.Author(Truncate(vars.Expand(tmpl.Author), embedTitleLimit), vars.Jump, avatarURL)
+	}
+
+	for _, field := range tmpl.Fields {
+		eb.AddField(Truncate(vars.Expand(field.Name), embedFieldNameLimit), Truncate(vars.Expand(field.Value), embedFieldValueLimit), field.Inline)
+	}
+
+	eb.Footer(TemplateFooter(guild, vars), vars.EmojiURL)
+}
+
+// TemplateTitle returns expanded title of guild's template.
+func TemplateTitle(guild *database.Guild, vars *TemplateVars) string {
+	return Truncate(vars.Expand(guild.StarboardTemplate().Title), embedTitleLimit)
+}
+
+// TemplateFooter returns expanded footer of guild's template prefixed with a star emote.
+func TemplateFooter(guild *database.Guild, vars *TemplateVars) string {
+	text := vars.Expand(guild.StarboardTemplate().Footer)
+	if vars.Emoji != "" {
+		text = fmt.Sprintf("%v %v", vars.Emoji, text)
+	}
+
+	return Truncate(text, embedFooterLimit)
+}
+
+// Truncate cuts text to a number of runes, ending it with an ellipsis if it was cut.
+func Truncate(text string, limit int) string {
+	if runes := []rune(text); len(runes) > limit {
+		return string(runes[:limit-1]) + "…"
+	}
+
+	return text
+}
+
+// FooterEmoji returns footer prefix and icon for a star count, taking star tiers into account.
+// guildEmojiURL is an icon of guild's custom star emote.
+func FooterEmoji(guild *database.Guild, count int, guildEmojiURL string) (string, string) {
+	if tier := guild.Tier(count); tier != nil {
+		if url, ok := EmojiURL(tier.Emoji); ok {
+			return "", url
+		}
+
+		return tier.Emoji, ""
+	}
+
+	if guild.IsGuildEmoji() {
+		return "", guildEmojiURL
+	}
+
+	return "⭐", ""
+}
+
+// EmojiURL returns CDN URL of a custom emoji formatted as <:name:id> or <a:name:id>.
+func EmojiURL(emoji string) (string, bool) {
+	if !strings.HasPrefix(emoji, "<") || !strings.HasSuffix(emoji, ">") {
+		return "", false
+	}
+
+	parts := strings.Split(strings.Trim(emoji, "<>"), ":")
+	if len(parts) != 3 {
+		return "", false
+	}
+
+	ext := "png"
+	if parts[0] == "a" {
+		ext = "gif"
+	}
+
+	return fmt.Sprintf("https://cdn.discordapp.com/emojis/%v.%v", parts[2], ext), true
+}
+
+// TemplatePreview renders a template with sample values.
+func TemplatePreview(guild *database.Guild, author *discordgo.User) *discordgo.MessageEmbed {
+	var (
+		eb   = embeds.NewBuilder()
+		vars = &TemplateVars{
+			Author:    author.Username,
+			Channel:   "general",
+			Count:     guild.MinimumStars,
+			Jump:      "https://discord.com/channels/@me",
+			Timestamp: time.Now(),
+			Content:   "This is how a starred message will look like.",
+		}
+	)
+
+	guildEmojiURL, _ := EmojiURL(guild.StarEmote)
+	vars.Emoji, vars.EmojiURL = FooterEmoji(guild, vars.Count, guildEmojiURL)
+
+	ApplyTemplate(eb, guild, vars, author.AvatarURL(""))
+	eb.Color(guild.Colour("", vars.Count))
+	eb.Timestamp(vars.Timestamp)
+	eb.Description(vars.Content)
+
+	tmpl := guild.StarboardTemplate()
+	if !tmpl.HideReply {
+		eb.Description(vars.Content + "\n\n> Replying to <@" + author.ID + ">\n> \n> An earlier message.")
+	}
+
+	if !tmpl.HideForwarded {
+		eb.AddField("Forwarded message", fmt.Sprintf("[Click here](%v)", vars.Jump))
+	}
+
+	return eb.Finalize()
+}
@@ -3,7 +3,9 @@ package utils
 import (
 	"errors"
 	"fmt"
+	"math"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -18,11 +20,22 @@ var (
 	// VideoURLRegex ...
 	VideoURLRegex = regexp.MustCompile(`(?i)(?:http(?:s?):)(?:[/|.|\w|\s|-])*\.(mp4|webm|mov|gifv)(?:(?:\?|&)\w+=\w+)*`)
 	// YoutubeRegex ...
-	YoutubeRegex = regexp.MustCompile(`(?i)https?:\/\/(?:www\.)?youtu(?:be)?\.(?:com|be)\/(?:watch\?v=)?\S+`)
+	YoutubeRegex = regexp.MustCompile(`(?i)https?:\/\/(?:(?:www|m|music)\.)?youtu(?:be)?\.(?:com|be)\/(?:watch\?v=)?\S+`)
 	// NumRegex is a terrible number regex. Gonna replace it with better code.
 	NumRegex = regexp.MustCompile(`([0-9]+)`)
 	// EmojiRegex matches some Unicode emojis, it's not perfect but better than nothing
 	EmojiRegex = regexp.MustCompile(`(\x{00a9}|\x{00ae}|[\x{2000}-\x{3300}]|\x{d83c}[\x{d000}-\x{dfff}]|\x{d83d}[\x{d000}-\x{dfff}]|\x{d83e}[\x{d000}-\x{dfff}])`)
+	// messageLinkRegex matches Discord message links, including PTB and Canary ones
+	messageLinkRegex = regexp.MustCompile(`(?i)^<?https?://(?:(?:ptb|canary)\.)?discord(?:app)?\.com/channels/(\d+)/(\d+)/(\d+)>?$`)
+	// durationRegex matches a single component of a duration like 7d or 12h
+	durationRegex = regexp.MustCompile(`(\d+)(w|d|h|m|s)`)
+	durationUnits = map[string]time.Duration{
+		"w": 7 * 24 * time.Hour,
+		"d": 24 * time.Hour,
+		"h": time.Hour,
+		"m": time.Minute,
+		"s": time.Second,
+	}
 	// EmbedColor is a default Discord embed color
 	EmbedColor = 16744576
 	// ErrNotEnoughArguments is a default error when not enough arguments were given
@@ -41,16 +54,16 @@ func EmbedTimestamp() string {
 func CreateDB(eventGuilds []*discordgo.Guild) error {
 	allGuilds := database.AllGuilds()
 	for _, guild := range allGuilds {
-		database.GuildCache[guild.ID] = guild
+		database.GuildCache.Set(guild.ID, guild)
 	}
 
 	newGuilds := make([]interface{}, 0)
 	for _, guild := range eventGuilds {
-		if _, ok := database.GuildCache[guild.ID]; !ok {
+		if _, ok := database.GuildCache.Lookup(guild.ID); !ok {
 			log.Infoln(guild.ID, "not found in database. Adding...")
 			g := database.NewGuild(guild.Name, guild.ID)
 			newGuilds = append(newGuilds, g)
-			database.GuildCache[g.ID] = g
+			database.GuildCache.Set(g.ID, g)
 		}
 	}
 
@@ -194,3 +207,64 @@ func nextMessageCreate(s *discordgo.Session) chan *discordgo.MessageCreate {
 
 	return out
 }
+
+// ParseDuration parses durations like 30m, 12h, 7d or 2w. Units can be combined, e.g. 1d12h.
+func ParseDuration(str string) (time.Duration, error) {
+	lower := strings.ToLower(str)
+	matches := durationRegex.FindAllStringSubmatch(lower, -1)
+	if len(matches) == 0 || strings.Join(durationRegex.FindAllString(lower, -1), "") != lower {
+		return 0, fmt.Errorf("unable to parse %v to a duration, examples: 30m, 12h, 7d, 2w", str)
+	}
+
+	var total time.Duration
+	for _, match := range matches {
+		n, err := strconv.ParseInt(match[1], 10, 64)
+		unit := durationUnits[match[2]]
+		if err != nil || time.Duration(n) > (math.MaxInt64-total)/unit {
+			return 0, fmt.Errorf("duration %v is too long", str)
+		}
+
+		total += time.Duration(n) * unit
+	}
+
+	if total == 0 {
+		return 0, fmt.Errorf("duration %v is zero", str)
+	}
+
+	return total, nil
+}
+
+// FormatDuration returns a human-readable duration rounded to minutes, e.g. 7d 12h.
+func FormatDuration(d time.Duration) string {
+	if d <= 0 {
+		return "none"
+	}
+
+	d = d.Round(time.Minute)
+	parts := make([]string, 0, 4)
+	for _, unit := range []struct {
+		suffix string
+		length time.Duration
+	}{{"d", 24 * time.Hour}, {"h", time.Hour}, {"m", time.Minute}} {
+		if n := d / unit.length; n > 0 {
+			parts = append(parts, fmt.Sprintf("%v%v", int64(n), unit.suffix))
+			d -= n * unit.length
+		}
+	}
+
+	if len(parts) == 0 {
+		return "<1m"
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// ParseMessageLink parses a Discord message link and returns guild, channel and message IDs.
+func ParseMessageLink(link string) (string, string, string, bool) {
+	match := messageLinkRegex.FindStringSubmatch(link)
+	if match == nil {
+		return "", "", "", false
+	}
+
+	return match[1], match[2], match[3], true
+}
@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		str     string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"30m", 30 * time.Minute, false},
+		{"12h", 12 * time.Hour, false},
+		{"2w", 14 * 24 * time.Hour, false},
+		{"1d12h", 36 * time.Hour, false},
+		{"7D", 7 * 24 * time.Hour, false},
+		{"", 0, true},
+		{"7", 0, true},
+		{"7days", 0, true},
+		{"d7", 0, true},
+		{"1d 2h", 0, true},
+		{"0d", 0, true},
+		{"99999999999999w", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.str, func(t *testing.T) {
+			got, err := ParseDuration(tt.str)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDuration(%q) error = %v, wantErr %v", tt.str, err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("ParseDuration(%q) = %v, want %v", tt.str, got, tt.want)
+			}
+		})
+	}
+}
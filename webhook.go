@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"sync"
+
+	"github.com/VTGare/Eugen/database"
+	"github.com/bwmarrin/discordgo"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	webhookName = "Eugen Starboard"
+)
+
+var (
+	webhookCache = &webhooks{byChannel: make(map[string]*discordgo.Webhook), byID: make(map[string]*discordgo.Webhook)}
+	// Discord rejects webhook usernames containing these words.
+	forbiddenWebhookName = regexp.MustCompile(`(?i)discord|clyde`)
+)
+
+type webhooks struct {
+	mu        sync.Mutex
+	byChannel map[string]*discordgo.Webhook
+	byID      map[string]*discordgo.Webhook
+}
+
+func (w *webhooks) store(wh *discordgo.Webhook) {
+	w.byChannel[wh.ChannelID] = wh
+	w.byID[wh.ID] = wh
+}
+
+// channelWebhook returns Eugen's webhook in a starboard channel, creating one if necessary.
+func (w *webhooks) channelWebhook(s *discordgo.Session, channelID string) (*discordgo.Webhook, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if wh, ok := w.byChannel[channelID]; ok {
+		return wh, nil
+	}
+
+	existing, err := s.ChannelWebhooks(channelID)
+	if err != nil {
+		return nil, fmt.Errorf("ChannelWebhooks(): %w", err)
+	}
+
+	for _, wh := range existing {
+		if wh.Token != "" && wh.User != nil && wh.User.ID == s.State.User.ID {
+			w.store(wh)
+			return wh, nil
+		}
+	}
+
+	wh, err := s.WebhookCreate(channelID, webhookName, "")
+	if err != nil {
+		return nil, fmt.Errorf("WebhookCreate(): %w", err)
+	}
+
+	w.store(wh)
+	return wh, nil
+}
+
+// webhook returns a webhook by its ID, fetching it if it isn't cached.
+func (w *webhooks) webhook(s *discordgo.Session, webhookID string) (*discordgo.Webhook, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if wh, ok := w.byID[webhookID]; ok {
+		return wh, nil
+	}
+
+	wh, err := s.Webhook(webhookID)
+	if err != nil {
+		return nil, fmt.Errorf("Webhook(): %w", err)
+	}
+
+	w.store(wh)
+	return wh, nil
+}
+
+// has reports whether a webhook is one of Eugen's cached webhooks.
+func (w *webhooks) has(webhookID string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	_, ok := w.byID[webhookID]
+	return ok
+}
+
+// forget removes a webhook from cache, e.g. if it was deleted by a moderator.
+func (w *webhooks) forget(channelID string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if wh, ok := w.byChannel[channelID]; ok {
+		delete(w.byID, wh.ID)
+		delete(w.byChannel, channelID)
+	}
+}
+
+// isWebhookRepost reports whether a message is a starboard post sent by Eugen's webhook.
+// Webhook messages aren't authored by the bot, so they'd be starboarded again otherwise.
+func isWebhookRepost(msg *discordgo.Message) bool {
+	if msg.WebhookID == "" {
+		return false
+	}
+
+	if webhookCache.has(msg.WebhookID) {
+		return true
+	}
+
+	repost, err := database.RepostByStarboard(msg.ChannelID, msg.ID)
+	if err != nil {
+		logrus.Warnf("isWebhookRepost() -> database.RepostByStarboard(): %v", err)
+		return false
+	}
+
+	return repost != nil
+}
+
+// sendWebhookRepost posts a starboard message on behalf of the original author.
+func sendWebhookRepost(s *discordgo.Session, channelID string, author *discordgo.User, msg *discordgo.MessageSend) (*discordgo.Message, string, error) {
+	wh, err := webhookCache.channelWebhook(s, channelID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	name := forbiddenWebhookName.ReplaceAllString(displayName(author), "***")
+
+	mentions := msg.AllowedMentions
+	if mentions == nil {
+		mentions = repostMentions()
+	}
+
+	params := &discordgo.WebhookParams{
+		Content:         msg.Content,
+		Username:        name,
+		AvatarURL:       author.AvatarURL(""),
+		Embeds:          msg.Embeds,
+		Files:           msg.Files,
+		AllowedMentions: mentions,
+	}
+
+	sent, err := s.WebhookExecute(wh.ID, wh.Token, true, params)
+	if err != nil {
+		webhookCache.forget(channelID)
+		return nil, "", fmt.Errorf("WebhookExecute(): %w", err)
+	}
+
+	return sent, wh.ID, nil
+}
+
+// bufferFiles reads files into memory, so they can be sent again if a webhook fails.
+// Returned function rewinds the files to their beginning.
+func bufferFiles(files []*discordgo.File) (func(), error) {
+	data := make([][]byte, len(files))
+	for i, f := range files {
+		b, err := io.ReadAll(f.Reader)
+		if err != nil {
+			return nil, err
+		}
+
+		data[i] = b
+		f.Reader = bytes.NewReader(b)
+	}
+
+	return func() {
+		for i, f := range files {
+			f.Reader = bytes.NewReader(data[i])
+		}
+	}, nil
+}
+
+// editWebhookRepost replaces an embed of a message sent by a webhook.
+func editWebhookRepost(s *discordgo.Session, webhookID, messageID string, embed *discordgo.MessageEmbed) error {
+	wh, err := webhookCache.webhook(s, webhookID)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.WebhookMessageEdit(wh.ID, wh.Token, messageID, &discordgo.WebhookEdit{
+		Embeds:          &[]*discordgo.MessageEmbed{embed},
+		AllowedMentions: repostMentions(),
+	})
+	if err != nil {
+		return fmt.Errorf("WebhookMessageEdit(): %w", err)
+	}
+
+	return nil
+}